@@ -2,9 +2,23 @@ package errorHandler
 
 import (
 	"fmt"
+	"io"
+	"os"
 )
 
+// where reported errors are written, tests can redirect it to capture them
+var Output io.Writer = os.Stdout
+
 func ReportError(lineNumber int, location string, errorMessage string) error {
-	fmt.Printf("[line %d] Error %s: %s \n", lineNumber, location, errorMessage)
+	if location == "" {
+		fmt.Fprintf(Output, "[line %d] Error: %s\n", lineNumber, errorMessage)
+	} else {
+		fmt.Fprintf(Output, "[line %d] Error %s: %s\n", lineNumber, location, errorMessage)
+	}
 	return fmt.Errorf("%s", errorMessage)
 }
+
+// warnings point out likely mistakes but don't stop the program
+func ReportWarning(lineNumber int, location string, message string) {
+	fmt.Fprintf(Output, "[line %d] Warning %s: %s\n", lineNumber, location, message)
+}
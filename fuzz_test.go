@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hamdan-khan/interpreter/errorHandler"
+	"github.com/hamdan-khan/interpreter/interpreter"
+	"github.com/hamdan-khan/interpreter/parser"
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// statements a fuzzed program may execute, so that generated infinite loops terminate
+const fuzzStepLimit = 10000
+
+// runs scanner, parser, resolver and interpreter on arbitrary input,
+// seeded with the conformance suite programs
+func FuzzInterpreter(f *testing.F) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*", "*.lox"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(source))
+	}
+	f.Add("while (true) {}")
+	f.Add("fun f() { f(); } f();")
+	f.Add("fun f() { return f; } print f()()();")
+
+	errorHandler.Output = io.Discard
+	f.Fuzz(func(t *testing.T, source string) {
+		scanner := token.NewScanner(source)
+		scanner.Scan()
+		if scanner.HadError {
+			return
+		}
+		p := parser.NewParser(scanner.Tokens)
+		statements, err := p.Parse()
+		if err != nil {
+			return
+		}
+
+		i := interpreter.NewInterpreter()
+		i.SetOutput(io.Discard)
+		i.SetStepLimit(fuzzStepLimit)
+		if err := interpreter.NewResolver(i).ResolveStmts(statements); err != nil {
+			return
+		}
+		i.Interpret(statements)
+		i.Close()
+	})
+}
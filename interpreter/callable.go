@@ -1,7 +1,14 @@
 package interpreter
 
+import (
+	"fmt"
+
+	"github.com/hamdan-khan/interpreter/token"
+)
+
 type Callable interface {
-	Arity() int
+	// minimum and maximum number of positional arguments, max is -1 if unbounded
+	Arity() (int, int)
 	Call(interpreter *Interpreter, arguments []any) (any, error)
 }
 
@@ -10,10 +17,24 @@ type NativeCallable struct {
 	arity int
 }
 
-func (nc *NativeCallable) Arity() int {
-	return nc.arity
+func (nc *NativeCallable) Arity() (int, int) {
+	return nc.arity, nc.arity
 }
 
 func (nc *NativeCallable) Call(interpreter *Interpreter, arguments []any) (any, error) {
 	return nc.fn(arguments)
 }
+
+// checks the number of positional arguments passed to a callable, errors are reported at tok
+func checkArity(tok token.Token, function Callable, count int) error {
+	min, max := function.Arity()
+	switch {
+	case min == max && count != min:
+		return NewRuntimeError(tok, fmt.Sprintf("Expected %d arguments but got %d.", min, count))
+	case max < 0 && count < min:
+		return NewRuntimeError(tok, fmt.Sprintf("Expected at least %d arguments but got %d.", min, count))
+	case max >= 0 && (count < min || count > max):
+		return NewRuntimeError(tok, fmt.Sprintf("Expected %d to %d arguments but got %d.", min, max, count))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/hamdan-khan/interpreter/syntax"
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// splits value into its first count values and, with rest, the remaining
+// ones. a value of the wrong shape is reported through mismatch instead of
+// an error, match arms simply don't match it. tok is used to report errors
+// from advancing the iterator
+func (i *Interpreter) unpack(tok token.Token, value any, count int, rest bool) (values []any, remaining []any, mismatch string, err error) {
+	var iterator Iterator
+	switch v := value.(type) {
+	case string:
+		iterator = &stringIterator{runes: []rune(v), index: -1}
+	case Iterable:
+		iterator = v.Iterator()
+	default:
+		return nil, nil, "Can only destructure strings, lists, ranges and generators", nil
+	}
+	// without a rest element the iterator is advanced once more to check
+	// there are no values left, so a generator runs up to its next yield
+	// before it is closed
+	defer closeIterator(iterator)
+
+	for len(values) < count {
+		ok, err := advance(tok, iterator)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if !ok {
+			if rest {
+				return nil, nil, fmt.Sprintf("Expected at least %d values to destructure but got %d.", count, len(values)), nil
+			}
+			return nil, nil, fmt.Sprintf("Expected %d values to destructure but got %d.", count, len(values)), nil
+		}
+		values = append(values, iterator.Value())
+	}
+
+	remaining = []any{}
+	for {
+		ok, err := advance(tok, iterator)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if !ok {
+			return values, remaining, "", nil
+		}
+		if !rest {
+			return nil, nil, fmt.Sprintf("Expected %d values to destructure but got more.", count), nil
+		}
+		remaining = append(remaining, iterator.Value())
+	}
+}
+
+func (i *Interpreter) VisitDestructuringVarStmt(stmt *syntax.DestructuringVar) (any, error) {
+	value, err := i.evaluate(stmt.Initializer)
+	if err != nil {
+		return nil, err
+	}
+	return nil, i.declarePattern(stmt.Pattern, value, stmt.Constant)
+}
+
+// defines the names bound by a declaration pattern, which only contains
+// names, wildcards and nested list patterns
+func (i *Interpreter) declarePattern(pattern syntax.Pattern, value any, constant bool) error {
+	switch p := pattern.(type) {
+	case *syntax.BindingPattern:
+		return i.declare(p.Name, value, constant)
+	case *syntax.ListPattern:
+		values, remaining, mismatch, err := i.unpack(p.Bracket, value, len(p.Elements), p.Rest != nil)
+		if err != nil {
+			return err
+		}
+		if mismatch != "" {
+			return NewRuntimeError(p.Bracket, mismatch)
+		}
+		for idx, element := range p.Elements {
+			if err := i.declarePattern(element, values[idx], constant); err != nil {
+				return err
+			}
+		}
+		if p.Rest != nil && p.Rest.Lexeme != "_" {
+			return i.declare(*p.Rest, NewList(remaining), constant)
+		}
+	}
+	return nil
+}
+
+func (i *Interpreter) declare(name token.Token, value any, constant bool) error {
+	if err := i.environment.CheckRedeclaration(name); err != nil {
+		return err
+	}
+	if i.tracer != nil {
+		i.tracer.assign(name.Lexeme, name.LineNumber, i.stringify(value))
+	}
+	if constant {
+		i.environment.DefineConstant(name.Lexeme, value)
+	} else {
+		i.environment.Define(name.Lexeme, value)
+	}
+	return nil
+}
+
+func (i *Interpreter) VisitDestructuringAssignExpr(expr *syntax.DestructuringAssign) (any, error) {
+	// the whole value is evaluated before any target is assigned, so
+	// [a, b] = [b, a] swaps
+	value, err := i.evaluate(expr.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.assignTarget(expr.Target, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (i *Interpreter) assignTarget(target syntax.Expr, value any) error {
+	switch t := target.(type) {
+	case *syntax.Variable:
+		return i.assignVariable(t.Name, t, value)
+	case *syntax.List:
+		elements := t.Elements
+		var spread *syntax.Spread
+		if len(elements) > 0 {
+			if s, ok := elements[len(elements)-1].(*syntax.Spread); ok {
+				spread = s
+				elements = elements[:len(elements)-1]
+			}
+		}
+		values, remaining, mismatch, err := i.unpack(t.Bracket, value, len(elements), spread != nil)
+		if err != nil {
+			return err
+		}
+		if mismatch != "" {
+			return NewRuntimeError(t.Bracket, mismatch)
+		}
+		for idx, element := range elements {
+			if err := i.assignTarget(element, values[idx]); err != nil {
+				return err
+			}
+		}
+		if spread != nil {
+			return i.assignTarget(spread.Expression, NewList(remaining))
+		}
+	}
+	return nil
+}
+
+func (i *Interpreter) VisitListExpr(expr *syntax.List) (any, error) {
+	elements := []any{}
+	for _, element := range expr.Elements {
+		spread, ok := element.(*syntax.Spread)
+		if !ok {
+			value, err := i.evaluate(element)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, value)
+			continue
+		}
+
+		value, err := i.evaluate(spread.Expression)
+		if err != nil {
+			return nil, err
+		}
+		values, err := i.spread(spread.Ellipsis, value)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, values...)
+	}
+	return NewList(elements), nil
+}
+
+// all the values of an iterable, in order
+func (i *Interpreter) spread(ellipsis token.Token, value any) ([]any, error) {
+	iterator, err := i.iterate(ellipsis, value)
+	if err != nil {
+		return nil, err
+	}
+	defer closeIterator(iterator)
+	values := []any{}
+	for {
+		ok, err := advance(ellipsis, iterator)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return values, nil
+		}
+		values = append(values, iterator.Value())
+	}
+}
+
+// a spread is only valid in a list literal or as a call argument, which
+// handle it, the parser rejects it everywhere else
+func (i *Interpreter) VisitSpreadExpr(expr *syntax.Spread) (any, error) {
+	return nil, NewRuntimeError(expr.Ellipsis, "Unexpected spread.")
+}
@@ -0,0 +1,95 @@
+package interpreter
+
+import (
+	"github.com/hamdan-khan/interpreter/syntax"
+)
+
+// the value of an enum declaration, its members are accessed as properties
+// e.g. Color.Red
+type Enum struct {
+	Name   string
+	Values []*EnumValue // in declaration order
+}
+
+// a member of an enum, only equal to itself
+type EnumValue struct {
+	Enum    *Enum
+	Name    string
+	Ordinal int64 // position in the declaration, starting at 0
+}
+
+func (e *Enum) String() string {
+	return "<enum " + e.Name + ">"
+}
+
+func (v *EnumValue) String() string {
+	return v.Enum.Name + "." + v.Name
+}
+
+func (e *Enum) member(name string) (*EnumValue, bool) {
+	for _, value := range e.Values {
+		if value.Name == name {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (i *Interpreter) VisitEnumStmt(stmt *syntax.Enum) (any, error) {
+	enum := &Enum{Name: stmt.Name.Lexeme}
+	for idx, member := range stmt.Members {
+		enum.Values = append(enum.Values, &EnumValue{Enum: enum, Name: member.Lexeme, Ordinal: int64(idx)})
+	}
+	if err := i.environment.CheckRedeclaration(stmt.Name); err != nil {
+		return nil, err
+	}
+	i.environment.Define(stmt.Name.Lexeme, enum)
+	return nil, nil
+}
+
+func (i *Interpreter) VisitGetExpr(expr *syntax.Get) (any, error) {
+	val, _, err := i.get(expr)
+	return val, err
+}
+
+// a property access that is a link of a chain, like call
+func (i *Interpreter) get(expr *syntax.Get) (val any, shorted bool, err error) {
+	object, shorted, err := i.evaluateLink(expr.Object)
+	if err != nil || shorted {
+		return nil, shorted, err
+	}
+	if expr.Optional && object == nil {
+		return nil, true, nil
+	}
+
+	name := expr.Name.Lexeme
+	switch o := object.(type) {
+	case *Enum:
+		if value, ok := o.member(name); ok {
+			return value, false, nil
+		}
+		if name == "values" {
+			// a new list on every call, changing it doesn't change the enum
+			return &NativeCallable{
+				fn: func(args []any) (any, error) {
+					values := make([]any, len(o.Values))
+					for idx, value := range o.Values {
+						values[idx] = value
+					}
+					return NewList(values), nil
+				},
+				arity: 0,
+			}, false, nil
+		}
+	case *EnumValue:
+		switch name {
+		case "name":
+			return o.Name, false, nil
+		case "ordinal":
+			return o.Ordinal, false, nil
+		}
+	default:
+		return nil, false, NewRuntimeError(expr.Name, "Only enums and their values have properties")
+	}
+	return nil, false, NewRuntimeError(expr.Name, "Undefined property '"+name+"'")
+}
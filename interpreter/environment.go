@@ -6,6 +6,8 @@ import (
 
 type Environment struct {
 	values map[string]any
+	// names declared with "const" or "let"
+	constants map[string]bool
 	// parent-pointer tree (for parent environemnt scope)
 	// also called the "cactus stack"
 	parent *Environment
@@ -14,16 +16,18 @@ type Environment struct {
 // for global scope
 func NewEnvironment() *Environment {
 	return &Environment{
-		values: make(map[string]any),
-		parent: nil,
+		values:    make(map[string]any),
+		constants: make(map[string]bool),
+		parent:    nil,
 	}
 }
 
 // for local scope
 func NewEnvironmentWithParent(parent *Environment) *Environment {
 	return &Environment{
-		values: make(map[string]any),
-		parent: parent,
+		values:    make(map[string]any),
+		constants: make(map[string]bool),
+		parent:    parent,
 	}
 }
 
@@ -31,6 +35,20 @@ func (e *Environment) Define(name string, value any) {
 	e.values[name] = value
 }
 
+func (e *Environment) DefineConstant(name string, value any) {
+	e.values[name] = value
+	e.constants[name] = true
+}
+
+// globals can be redeclared, except constants. local redeclarations are
+// already rejected by the resolver
+func (e *Environment) CheckRedeclaration(name token.Token) error {
+	if e.constants[name.Lexeme] {
+		return NewRuntimeError(name, "Cannot redeclare a constant")
+	}
+	return nil
+}
+
 func (e *Environment) Get(token token.Token) (any, error) {
 	if value, ok := e.values[token.Lexeme]; ok {
 		return value, nil
@@ -46,6 +64,10 @@ func (e *Environment) Assign(token token.Token, value any) error {
 	// assignment cannot create a new variable, the var we're assinging
 	// to must be defined first
 	if _, ok := e.values[token.Lexeme]; ok {
+		// assignments to local constants are already rejected by the resolver
+		if e.constants[token.Lexeme] {
+			return NewRuntimeError(token, "Cannot assign to a constant")
+		}
 		e.values[token.Lexeme] = value
 		return nil
 	}
@@ -30,3 +30,17 @@ func (e *Return) Error() string {
 func NewReturn(value any) error {
 	return &Return{Value: value}
 }
+
+// unwinds the body of a function like Return, for a "return f(...)" in
+// tail position. the function that was returning from makes the call
+// instead, so tail recursion doesn't grow the go stack
+type tailCall struct {
+	function  *Function
+	paren     token.Token
+	arguments []any
+	named     []namedArgument
+}
+
+func (e *tailCall) Error() string {
+	return ""
+}
@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"github.com/hamdan-khan/interpreter/syntax"
+	"github.com/hamdan-khan/interpreter/token"
 )
 
 type Function struct {
@@ -9,33 +10,130 @@ type Function struct {
 	Closure     *Environment // surrounding environment in which the function is declared
 }
 
+// an evaluated named argument e.g. b: 3
+type namedArgument struct {
+	Name  token.Token
+	Value any
+}
+
 func NewFunction(declaration *syntax.Function, closure *Environment) *Function {
 	return &Function{Declaration: declaration, Closure: closure}
 }
 
+// the number of positional arguments has already been checked against Arity
 func (f *Function) Call(interpreter *Interpreter, arguments []any) (any, error) {
-	// environment local to the called function with all the function's arguments
-	env := NewEnvironmentWithParent(f.Closure)
+	return f.call(interpreter, token.Token{}, arguments, nil)
+}
 
-	for i, param := range f.Declaration.Params {
-		env.Define(param.Lexeme, arguments[i])
-	}
+func (f *Function) call(interpreter *Interpreter, paren token.Token, positional []any, named []namedArgument) (any, error) {
+	// a trampoline: tail calls made by the body come back here and run in
+	// this loop, instead of recursing deeper
+	for {
+		// environment local to the called function with all the function's arguments
+		env, err := f.bind(interpreter, paren, positional, named)
+		if err != nil {
+			return nil, err
+		}
+
+		// the body of a generator only runs once values are asked for
+		if f.Declaration.Generator {
+			return newGenerator(interpreter, f, env), nil
+		}
 
-	err := interpreter.executeBlock(f.Declaration.Body, env)
-	if err != nil {
+		err = interpreter.executeBlock(f.Declaration.Body, env)
+		if err == nil {
+			return nil, nil
+		}
 		// return disguised as error is used to unwind the stack of statements
 		// similar to Java's exception throwing
-		if ret, ok := err.(*Return); ok {
+		switch ret := err.(type) {
+		case *Return:
 			return ret.Value, nil
+		case *tailCall:
+			f, paren, positional, named = ret.function, ret.paren, ret.arguments, ret.named
+		default:
+			// if it's not a return error, it's a runtime error
+			return nil, err
 		}
-		// if it's not a return error, it's a runtime error
-		return nil, err
 	}
-	return nil, nil
 }
 
-func (f *Function) Arity() int {
-	return len(f.Declaration.Params)
+// defines every parameter in a new environment: from the positional
+// arguments, then the named ones, then the default values
+func (f *Function) bind(interpreter *Interpreter, paren token.Token, positional []any, named []namedArgument) (*Environment, error) {
+	params := f.Declaration.Params
+	values := make([]any, len(params))
+	given := make([]bool, len(params))
+	rest := []any{}
+
+	for idx, arg := range positional {
+		switch {
+		case idx < len(params) && !params[idx].Variadic:
+			values[idx] = arg
+			given[idx] = true
+		case len(params) > 0 && params[len(params)-1].Variadic:
+			rest = append(rest, arg)
+		default:
+			return nil, checkArity(paren, f, len(positional))
+		}
+	}
+
+	for _, arg := range named {
+		idx := f.parameterIndex(arg.Name.Lexeme)
+		if idx < 0 {
+			return nil, NewRuntimeError(arg.Name, "No parameter named '"+arg.Name.Lexeme+"'")
+		}
+		if given[idx] {
+			return nil, NewRuntimeError(arg.Name, "Argument '"+arg.Name.Lexeme+"' was already passed")
+		}
+		values[idx] = arg.Value
+		given[idx] = true
+	}
+
+	env := NewEnvironmentWithParent(f.Closure)
+	for idx, param := range params {
+		switch {
+		case param.Variadic:
+			env.Define(param.Name.Lexeme, NewList(rest))
+		case given[idx]:
+			env.Define(param.Name.Lexeme, values[idx])
+		case param.Default != nil:
+			// evaluated on every call, and can refer to the parameters before it
+			value, err := interpreter.evaluateIn(param.Default, env)
+			if err != nil {
+				return nil, err
+			}
+			env.Define(param.Name.Lexeme, value)
+		default:
+			return nil, NewRuntimeError(paren, "Missing argument for parameter '"+param.Name.Lexeme+"'")
+		}
+	}
+	return env, nil
+}
+
+// position of the parameter that can be passed by the given name, -1 if there is none
+func (f *Function) parameterIndex(name string) int {
+	for idx, param := range f.Declaration.Params {
+		if param.Name.Lexeme == name && !param.Variadic {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (f *Function) Arity() (int, int) {
+	// parameters with a default value always come after the required ones
+	min, max := 0, 0
+	for _, param := range f.Declaration.Params {
+		if param.Variadic {
+			return min, -1
+		}
+		if param.Default == nil {
+			min++
+		}
+		max++
+	}
+	return min, max
 }
 
 func (f *Function) String() string {
@@ -0,0 +1,226 @@
+package interpreter
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// the interpreter evaluates code by recursing through go calls, so a
+// generator can't simply return from the middle of its body and come back
+// later. instead the body runs on its own goroutine with its own copy of
+// the interpreter state, and control is handed back and forth over
+// channels: only one side runs at a time, the other one is blocked.
+//
+// a suspended body keeps its goroutine blocked, so generators are closed
+// explicitly: when a for-in loop or a destructuring is done with them, or
+// by Interpreter.Close once the program ends. a generator the program drops
+// while it is suspended is queued by the garbage collector and closed by
+// the interpreter the next time a generator is created, the collector
+// never waits for a body to unwind.
+
+// returned from "yield" to unwind the body of a generator that is closed
+var errGeneratorClosed = errors.New("generator closed")
+
+// what the generator's goroutine needs, kept apart from Generator so a
+// dropped generator can be collected while its goroutine is blocked
+type generatorBody struct {
+	resume  chan bool // true to continue past the current yield, false to stop
+	results chan generatorResult
+	closed  bool // closed while suspended, it won't run again
+}
+
+// the suspended generator bodies of a program, shared by the interpreter
+// and its forks
+type generators struct {
+	// keyed by body rather than by generator, so a generator nothing else
+	// references can still be collected
+	suspended map[*generatorBody]struct{}
+
+	mu        sync.Mutex // guards abandoned, which the collector appends to
+	abandoned []*generatorBody
+}
+
+func newGenerators() *generators {
+	return &generators{suspended: make(map[*generatorBody]struct{})}
+}
+
+// runs on the garbage collector's cleanup goroutine, so it only queues
+func (gs *generators) abandon(body *generatorBody) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.abandoned = append(gs.abandoned, body)
+}
+
+// closes the bodies of the generators that were dropped while suspended
+func (gs *generators) reap() {
+	gs.mu.Lock()
+	abandoned := gs.abandoned
+	gs.abandoned = nil
+	gs.mu.Unlock()
+	for _, body := range abandoned {
+		gs.close(body)
+	}
+}
+
+// closes every suspended body
+func (gs *generators) closeAll() {
+	for body := range gs.suspended {
+		gs.close(body)
+	}
+}
+
+func (gs *generators) close(body *generatorBody) {
+	if _, ok := gs.suspended[body]; !ok {
+		return
+	}
+	delete(gs.suspended, body)
+	body.closed = true
+	body.resume <- false
+	<-body.results
+}
+
+type generatorResult struct {
+	value    any
+	done     bool  // the body finished, value is meaningless
+	err      error // runtime error that ended the body
+	panicked any   // forwarded to the consumer instead of crashing the process
+}
+
+// the value returned by calling a generator function, it is both the
+// Iterable and its single-use Iterator
+type Generator struct {
+	function *Function
+	body     *generatorBody
+	start    func() // runs the body on a new goroutine
+
+	generators *generators
+	started    bool
+	running    bool // the body is running, the generator can't be resumed
+	done       bool
+	index      int64
+	current    any
+}
+
+func newGenerator(interpreter *Interpreter, function *Function, env *Environment) *Generator {
+	interpreter.generators.reap()
+	body := &generatorBody{
+		resume:  make(chan bool),
+		results: make(chan generatorResult),
+	}
+
+	// the goroutine gets its own interpreter so its environment isn't mixed
+	// with the caller's, the counters are shared as only one side runs at a time
+	fork := &Interpreter{
+		globals:     interpreter.globals,
+		environment: interpreter.globals,
+		locals:      interpreter.locals,
+		tracer:      interpreter.tracer,
+		out:         interpreter.out,
+		stepLimit:   interpreter.stepLimit,
+		counters:    interpreter.counters,
+		generators:  interpreter.generators,
+		generator:   body,
+	}
+	statements := function.Declaration.Body
+
+	g := &Generator{function: function, body: body, generators: interpreter.generators}
+	g.start = func() {
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					body.results <- generatorResult{done: true, panicked: r}
+				}
+			}()
+			err := fork.executeBlock(statements, env)
+			var ret *Return
+			if errors.Is(err, errGeneratorClosed) || errors.As(err, &ret) {
+				err = nil
+			}
+			body.results <- generatorResult{done: true, err: err}
+		}()
+	}
+	// the cleanup mustn't reference g, which the body doesn't either
+	runtime.AddCleanup(g, interpreter.generators.abandon, body)
+	return g
+}
+
+// suspends the body until the consumer asks for the next value
+func (b *generatorBody) yield(value any) error {
+	b.results <- generatorResult{value: value}
+	if !<-b.resume {
+		return errGeneratorClosed
+	}
+	return nil
+}
+
+func (g *Generator) Iterator() Iterator {
+	return g
+}
+
+// runs the body until its next yield, false once it has finished
+func (g *Generator) Next() (bool, error) {
+	// the body asking for its own next value would wait for itself forever
+	if g.running {
+		return false, errors.New("Generator is already running.")
+	}
+	if g.done || g.body.closed {
+		return false, nil
+	}
+	// set before the body gets control, which it can use to call Next
+	g.running = true
+	if g.started {
+		g.body.resume <- true
+	} else {
+		g.started = true
+		g.generators.suspended[g.body] = struct{}{}
+		g.start()
+	}
+
+	result := <-g.body.results
+	g.running = false
+	if result.panicked != nil {
+		g.finish()
+		panic(result.panicked)
+	}
+	if result.done {
+		g.finish()
+		return false, result.err
+	}
+	g.index++
+	g.current = result.value
+	return true, nil
+}
+
+// true once the body has finished or was closed, Next won't produce more values
+func (g *Generator) Done() bool {
+	return g.done || g.body.closed
+}
+
+func (g *Generator) Key() any {
+	return g.index - 1
+}
+
+func (g *Generator) Value() any {
+	return g.current
+}
+
+// stops a suspended generator, its body unwinds from the current yield
+// without running any more statements. a running generator is left alone,
+// the body closing itself e.g. from a for-in over itself can't stop there
+func (g *Generator) Close() {
+	if g.running {
+		return
+	}
+	g.done = true
+	g.generators.close(g.body)
+}
+
+func (g *Generator) finish() {
+	g.done = true
+	delete(g.generators.suspended, g.body)
+}
+
+func (g *Generator) String() string {
+	return "<generator " + g.function.Declaration.Name.Lexeme + ">"
+}
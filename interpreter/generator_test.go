@@ -0,0 +1,126 @@
+package interpreter
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// the goroutine of a closed generator exits right after its last result
+// is received, so it is given a moment to go
+func waitForGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d generator goroutines still running", runtime.NumGoroutine()-want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSuspendedGeneratorsAreClosedByClose(t *testing.T) {
+	source := `
+fun naturals() {
+  var n = 0;
+  while (true) yield n++;
+}
+var kept = [];
+for (i in 0..100) {
+  var g = naturals();
+  next(g);
+  kept = [...kept, [g]];
+}
+`
+	before := runtime.NumGoroutine()
+	i := NewInterpreter()
+	if err := run(t, i, source); err != nil {
+		t.Fatal(err)
+	}
+	if len(i.generators.suspended) != 100 {
+		t.Fatalf("got %d suspended generators, want 100", len(i.generators.suspended))
+	}
+	i.Close()
+	if len(i.generators.suspended) != 0 {
+		t.Fatalf("got %d suspended generators after Close, want 0", len(i.generators.suspended))
+	}
+	waitForGoroutines(t, before)
+}
+
+// generators dropped while suspended are closed while the program still
+// runs, when it creates another generator
+func TestAbandonedGeneratorsAreClosed(t *testing.T) {
+	source := `
+fun naturals() {
+  var n = 0;
+  while (true) yield n++;
+}
+fun abandon() {
+  var g = naturals();
+  next(g);
+  next(g);
+}
+for (i in 0..100) abandon();
+`
+	before := runtime.NumGoroutine()
+	i := NewInterpreter()
+	if err := run(t, i, source); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(i.generators.suspended) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d abandoned generators still suspended", len(i.generators.suspended))
+		}
+		// cleanups run on their own goroutine after a collection
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+		if err := run(t, i, "naturals();"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitForGoroutines(t, before)
+}
+
+func TestConsumedGeneratorsAreClosed(t *testing.T) {
+	source := `
+fun naturals() {
+  var n = 0;
+  while (true) yield n++;
+}
+fun first(g) {
+  for (x in g) return x;
+}
+for (i in 0..100) first(naturals());
+`
+	before := runtime.NumGoroutine()
+	i := NewInterpreter()
+	if err := run(t, i, source); err != nil {
+		t.Fatal(err)
+	}
+	if len(i.generators.suspended) != 0 {
+		t.Fatalf("got %d suspended generators, want 0", len(i.generators.suspended))
+	}
+	waitForGoroutines(t, before)
+}
+
+// the steps run by generator bodies count towards the limit of the whole
+// program, not only towards a copy of the counter each generator has
+func TestGeneratorsShareStepLimit(t *testing.T) {
+	source := `
+fun count() {
+  for (var n = 0; n < 10; n = n + 1) yield n;
+}
+var all = [];
+for (i in 0..100) all = [...all, ...count()];
+`
+	i := NewInterpreter()
+	i.SetStepLimit(1000)
+	err := run(t, i, source)
+	i.Close()
+	if !errors.Is(err, ErrStepLimit) {
+		t.Fatalf("got %v, want the step limit", err)
+	}
+}
@@ -0,0 +1,54 @@
+package interpreter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hamdan-khan/interpreter/errorHandler"
+	"github.com/hamdan-khan/interpreter/parser"
+	"github.com/hamdan-khan/interpreter/syntax"
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// scans and parses the source, failing the test on a syntax error. the
+// statements' line numbers are returned for the tracer
+func parse(t *testing.T, source string) ([]syntax.Stmt, map[syntax.Stmt]int) {
+	t.Helper()
+	scanner := token.NewScanner(source)
+	scanner.Scan()
+	p := parser.NewParser(scanner.Tokens)
+	statements, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return statements, p.Lines
+}
+
+// resolves and runs the statements with i, discarding what they print
+func interpret(t *testing.T, i *Interpreter, statements []syntax.Stmt) error {
+	t.Helper()
+	i.SetOutput(io.Discard)
+	if err := NewResolver(i).ResolveStmts(statements); err != nil {
+		t.Fatal(err)
+	}
+	return i.Interpret(statements)
+}
+
+// parses, resolves and runs the source with i
+func run(t *testing.T, i *Interpreter, source string) error {
+	t.Helper()
+	statements, _ := parse(t, source)
+	return interpret(t, i, statements)
+}
+
+// redirects reported errors and warnings to the returned buffer until the
+// test ends
+func captureReports(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var output bytes.Buffer
+	errorHandler.Output = &output
+	t.Cleanup(func() { errorHandler.Output = os.Stdout })
+	return &output
+}
@@ -1,9 +1,13 @@
 package interpreter
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/hamdan-khan/interpreter/syntax"
 	"github.com/hamdan-khan/interpreter/token"
@@ -13,23 +17,64 @@ type Interpreter struct {
 	globals     *Environment
 	environment *Environment
 	locals      map[syntax.Expr]int
+	tracer      *Tracer
+	out         io.Writer // where "print" writes to
+
+	stepLimit int // maximum number of statements to execute, 0 means no limit
+	// shared with the interpreters running generator bodies, so the step
+	// limit and the call depth cover the whole program
+	counters   *counters
+	generators *generators
+
+	// set while running the body of a generator, see generator.go
+	generator *generatorBody
 }
 
+type counters struct {
+	steps     int // number of executed statements
+	callDepth int // number of active function calls
+}
+
+// deepest allowed function call nesting, exceeding it is reported as
+// a runtime error instead of overflowing the go stack
+const maxCallDepth = 10000
+
+// returned when the program executes more statements than its step budget
+var ErrStepLimit = errors.New("Step limit exceeded.")
+
 func NewInterpreter() *Interpreter {
 	globals := NewEnvironment()
 
-	globals.Define("clock", &NativeCallable{
-		fn: func(args []any) (any, error) {
-			return time.Now().UnixNano() / 1e6, nil
-		},
-		arity: 0,
-	})
-
-	return &Interpreter{
+	i := &Interpreter{
 		globals:     globals,
 		environment: globals,
 		locals:      make(map[syntax.Expr]int),
+		out:         os.Stdout,
+		counters:    &counters{},
+		generators:  newGenerators(),
 	}
+	i.defineNatives()
+	return i
+}
+
+func (i *Interpreter) SetOutput(out io.Writer) {
+	i.out = out
+}
+
+// limits the number of statements a program may execute, 0 disables the limit
+func (i *Interpreter) SetStepLimit(limit int) {
+	i.stepLimit = limit
+}
+
+// closes the generators the program left suspended, which would otherwise
+// keep their goroutines blocked, call it once the program is done
+func (i *Interpreter) Close() {
+	i.generators.closeAll()
+}
+
+// enables execution tracing, a nil tracer disables it
+func (i *Interpreter) SetTracer(tracer *Tracer) {
+	i.tracer = tracer
 }
 
 func (i *Interpreter) resolve(expr syntax.Expr, depth int) {
@@ -46,6 +91,34 @@ func (i *Interpreter) Interpret(stmts []syntax.Stmt) error {
 	return nil
 }
 
+// calls a global function by name with the given arguments,
+// used by the test runner to invoke test functions
+func (i *Interpreter) CallGlobal(name string, args ...any) (any, error) {
+	nameToken := token.Token{TokenType: token.IDENTIFIER, Lexeme: name}
+	callee, err := i.globals.Get(nameToken)
+	if err != nil {
+		return nil, err
+	}
+	function, ok := callee.(Callable)
+	if !ok {
+		return nil, NewRuntimeError(nameToken, "Callee must be a function")
+	}
+	if err := checkArity(nameToken, function, len(args)); err != nil {
+		return nil, err
+	}
+	return function.Call(i, args)
+}
+
+// evaluates the expression with env as the current environment
+func (i *Interpreter) evaluateIn(expr syntax.Expr, env *Environment) (any, error) {
+	prev := i.environment
+	defer func() {
+		i.environment = prev
+	}()
+	i.environment = env
+	return i.evaluate(expr)
+}
+
 // recursively evaluates given expression
 // uses visitor pattern to implement functions for each expressions (todo: clarify)
 func (i *Interpreter) evaluate(expr syntax.Expr) (any, error) {
@@ -53,6 +126,15 @@ func (i *Interpreter) evaluate(expr syntax.Expr) (any, error) {
 }
 
 func (i *Interpreter) execute(stmt syntax.Stmt) (any, error) {
+	if i.stepLimit > 0 {
+		i.counters.steps++
+		if i.counters.steps > i.stepLimit {
+			return nil, ErrStepLimit
+		}
+	}
+	if i.tracer != nil {
+		i.tracer.statement(stmt)
+	}
 	return stmt.Accept(i)
 }
 
@@ -92,8 +174,7 @@ func (i *Interpreter) VisitVarStmt(stmt *syntax.Var) (any, error) {
 		}
 		val = v
 	}
-	i.environment.Define(stmt.Name.Lexeme, val)
-	return nil, nil
+	return nil, i.declare(stmt.Name, val, stmt.Constant)
 }
 
 func (i *Interpreter) VisitVariableExpr(expr *syntax.Variable) (any, error) {
@@ -114,14 +195,92 @@ func (i *Interpreter) VisitAssignExpr(expr *syntax.Assign) (any, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := i.assignVariable(expr.Name, expr, val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
 
+func (i *Interpreter) assignVariable(name token.Token, expr syntax.Expr, val any) error {
 	distance, ok := i.locals[expr]
 	if ok {
-		i.environment.AssignAt(distance, expr.Name, val)
-	} else {
-		i.globals.Assign(expr.Name, val)
+		i.environment.AssignAt(distance, name, val)
+	} else if err := i.globals.Assign(name, val); err != nil {
+		return err
 	}
-	return val, nil
+	if i.tracer != nil {
+		i.tracer.assign(name.Lexeme, name.LineNumber, i.stringify(val))
+	}
+	return nil
+}
+
+// binary operator applied by each compound assignment operator
+var compoundOperators = map[token.TokenType]token.TokenType{
+	token.PLUS_EQUAL:    token.PLUS,
+	token.MINUS_EQUAL:   token.MINUS,
+	token.STAR_EQUAL:    token.STAR,
+	token.SLASH_EQUAL:   token.SLASH,
+	token.PERCENT_EQUAL: token.PERCENT,
+}
+
+func (i *Interpreter) VisitCompoundAssignExpr(expr *syntax.CompoundAssign) (any, error) {
+	current, err := i.lookupVariable(expr.Name, expr)
+	if err != nil {
+		return nil, err
+	}
+	value, err := i.evaluate(expr.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := i.compound(expr.Operator, current, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.assignVariable(expr.Name, expr, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (i *Interpreter) VisitIncrementExpr(expr *syntax.Increment) (any, error) {
+	current, err := i.lookupVariable(expr.Name, expr)
+	if err != nil {
+		return nil, err
+	}
+	result, err := i.increment(expr.Operator, current)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.assignVariable(expr.Name, expr, result); err != nil {
+		return nil, err
+	}
+
+	if expr.Prefix {
+		return result, nil
+	}
+	return current, nil
+}
+
+// applies the binary operator of a compound assignment, errors are still
+// reported at the "+=" token
+func (i *Interpreter) compound(operator token.Token, current any, value any) (any, error) {
+	binary := operator
+	binary.TokenType = compoundOperators[operator.TokenType]
+	return i.binary(binary, current, value)
+}
+
+// the value after applying ++ or --
+func (i *Interpreter) increment(operator token.Token, current any) (any, error) {
+	if !isNumber(current) {
+		return nil, NewRuntimeError(operator, "Operator must be a number")
+	}
+	arithmetic := operator
+	arithmetic.TokenType = token.PLUS
+	if operator.TokenType == token.MINUS_MINUS {
+		arithmetic.TokenType = token.MINUS
+	}
+	return i.arithmetic(arithmetic, current, int64(1))
 }
 
 func (i *Interpreter) VisitIfStmt(stmt *syntax.If) (any, error) {
@@ -142,7 +301,7 @@ func (i *Interpreter) VisitPrintStmt(stmt *syntax.Print) (any, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Printf("%v\n", i.stringify(val))
+	fmt.Fprintf(i.out, "%v\n", i.stringify(val))
 	return nil, nil
 }
 
@@ -171,9 +330,119 @@ func (i *Interpreter) VisitWhileStmt(stmt *syntax.While) (any, error) {
 	return nil, nil
 }
 
+func (i *Interpreter) VisitForInStmt(stmt *syntax.ForIn) (any, error) {
+	iterable, err := i.evaluate(stmt.Iterable)
+	if err != nil {
+		return nil, err
+	}
+	iterator, err := i.iterate(stmt.In, iterable)
+	if err != nil {
+		return nil, err
+	}
+	defer closeIterator(iterator)
+
+	for {
+		ok, err := advance(stmt.In, iterator)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+
+		// a new environment per iteration, so closures created in the
+		// body capture that iteration's variables
+		environment := NewEnvironmentWithParent(i.environment)
+		if stmt.Key != nil {
+			environment.Define(stmt.Key.Lexeme, iterator.Key())
+		}
+		environment.Define(stmt.Value.Lexeme, iterator.Value())
+		if err := i.executeBlock([]syntax.Stmt{stmt.Body}, environment); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (i *Interpreter) VisitMatchStmt(stmt *syntax.Match) (any, error) {
+	subject, err := i.evaluate(stmt.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arm := range stmt.Arms {
+		// names bound by the pattern live in a scope of their own, like the resolver expects
+		environment := NewEnvironmentWithParent(i.environment)
+		matched, err := i.matchPattern(arm.Pattern, subject, environment)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return nil, i.executeBlock([]syntax.Stmt{arm.Body}, environment)
+		}
+	}
+	// no arm matched, nothing to do
+	return nil, nil
+}
+
+// tests the value against the pattern, defining bound names in env
+func (i *Interpreter) matchPattern(pattern syntax.Pattern, value any, env *Environment) (bool, error) {
+	switch p := pattern.(type) {
+	case *syntax.LiteralPattern:
+		// the resolver resolves a pattern like Color.Red inside the arm's scope
+		literal, err := i.evaluateIn(p.Value, env)
+		if err != nil {
+			return false, err
+		}
+		return i.isEqual(value, literal), nil
+	case *syntax.WildcardPattern:
+		return true, nil
+	case *syntax.BindingPattern:
+		env.Define(p.Name.Lexeme, value)
+		return true, nil
+	case *syntax.ListPattern:
+		values, remaining, mismatch, err := i.unpack(p.Bracket, value, len(p.Elements), p.Rest != nil)
+		if mismatch != "" || err != nil {
+			return false, err
+		}
+		for idx, element := range p.Elements {
+			matched, err := i.matchPattern(element, values[idx], env)
+			if !matched || err != nil {
+				return false, err
+			}
+		}
+		if p.Rest != nil && p.Rest.Lexeme != "_" {
+			env.Define(p.Rest.Lexeme, NewList(remaining))
+		}
+		return true, nil
+	case *syntax.AlternativePattern:
+		for _, alternative := range p.Alternatives {
+			matched, err := i.matchPattern(alternative, value, env)
+			if matched || err != nil {
+				return matched, err
+			}
+		}
+	}
+	return false, nil
+}
+
+func (i *Interpreter) VisitYieldStmt(stmt *syntax.Yield) (any, error) {
+	var value any = nil
+	if stmt.Value != nil {
+		v, err := i.evaluate(stmt.Value)
+		if err != nil {
+			return nil, err
+		}
+		value = v
+	}
+	return nil, i.generator.yield(value)
+}
+
 func (i *Interpreter) VisitFunctionStmt(stmt *syntax.Function) (any, error) {
 	// create a function object with the current environment as its closure
 	fn := NewFunction(stmt, i.environment)
+	if err := i.environment.CheckRedeclaration(stmt.Name); err != nil {
+		return nil, err
+	}
 	i.environment.Define(stmt.Name.Lexeme, fn)
 	return nil, nil
 }
@@ -181,6 +450,25 @@ func (i *Interpreter) VisitFunctionStmt(stmt *syntax.Function) (any, error) {
 func (i *Interpreter) VisitReturnStmt(stmt *syntax.Return) (any, error) {
 	var val any = nil
 	var err error
+	// traced calls aren't optimized, so every call is shown entering and returning
+	if stmt.TailCall && i.tracer == nil {
+		call := stmt.Value.(*syntax.Call)
+		function, args, named, err := i.evaluateCall(call)
+		if err != nil {
+			return nil, err
+		}
+		if function == nil {
+			return nil, NewReturn(nil)
+		}
+		if fn, ok := function.(*Function); ok {
+			return nil, &tailCall{function: fn, paren: call.Paren, arguments: args, named: named}
+		}
+		val, err = i.invoke(call, function, args, named)
+		if err != nil {
+			return nil, err
+		}
+		return nil, NewReturn(val)
+	}
 	if stmt.Value != nil {
 		val, err = i.evaluate(stmt.Value)
 		if err != nil {
@@ -221,13 +509,11 @@ func (i *Interpreter) VisitUnaryExpr(expr *syntax.Unary) (any, error) {
 	// post order traversal (left -> right subtree of AST)
 	switch expr.Operator.TokenType {
 	case token.MINUS:
-		val, err := i.checkNumberOperand(expr.Operator, right)
-		if err != nil {
-			return nil, err
-		}
-		return -val, nil
+		return i.negate(expr.Operator, right)
 	case token.EXCLAMATION:
 		return !i.isTruthy(right), nil
+	case token.TILDE:
+		return i.bitwiseNot(expr.Operator, right)
 	}
 
 	return nil, nil
@@ -242,52 +528,26 @@ func (i *Interpreter) VisitBinaryExpr(expr *syntax.Binary) (any, error) {
 	if err != nil {
 		return nil, err
 	}
+	return i.binary(expr.Operator, left, right)
+}
 
-	switch expr.Operator.TokenType {
-	case token.MINUS:
-		leftVal, rightVal, err := i.checkNumberOperands(expr.Operator, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return leftVal - rightVal, nil
-	case token.SLASH:
-		leftVal, rightVal, err := i.checkNumberOperands(expr.Operator, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return leftVal / rightVal, nil
-	case token.STAR:
-		leftVal, rightVal, err := i.checkNumberOperands(expr.Operator, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return leftVal * rightVal, nil
+// applies a binary operator to two evaluated operands
+// todo: once classes exist, dispatch operators on instances to methods like
+// __add__ and __lt__, after the fast paths for the built-in types
+func (i *Interpreter) binary(operator token.Token, left any, right any) (any, error) {
+	switch operator.TokenType {
+	case token.MINUS, token.SLASH, token.STAR, token.PERCENT:
+		return i.arithmetic(operator, left, right)
+	case token.STAR_STAR:
+		return i.power(operator, left, right)
+	case token.AMPERSAND, token.PIPE, token.CARET, token.LESS_LESS, token.GREATER_GREATER:
+		return i.bitwise(operator, left, right)
 	case token.PLUS:
-		return i.executeAdd(left, right), nil
-	case token.GREATER:
-		leftVal, rightVal, err := i.checkNumberOperands(expr.Operator, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return leftVal > rightVal, nil
-	case token.GREATER_EQUAL:
-		leftVal, rightVal, err := i.checkNumberOperands(expr.Operator, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return leftVal >= rightVal, nil
-	case token.LESS:
-		leftVal, rightVal, err := i.checkNumberOperands(expr.Operator, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return leftVal < rightVal, nil
-	case token.LESS_EQUAL:
-		leftVal, rightVal, err := i.checkNumberOperands(expr.Operator, left, right)
-		if err != nil {
-			return nil, err
-		}
-		return leftVal <= rightVal, nil
+		return i.executeAdd(operator, left, right)
+	case token.DOT_DOT:
+		return i.newRange(operator, left, right)
+	case token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL:
+		return i.compare(operator, left, right)
 	case token.EQUAL_EQUAL:
 		return i.isEqual(left, right), nil
 	case token.NOT_EQUAL:
@@ -306,11 +566,17 @@ func (i *Interpreter) VisitLogicalExpr(expr *syntax.Logical) (any, error) {
 	// short circuit evaluation (jump ahead):
 	// for "or", if the left operand is truthy, we don't evaluate the right operand
 	// for "and", if the left operand is falsy, we don't evaluate the right operand
-	if expr.Operator.TokenType == token.OR {
+	// for "??", if the left operand is not nil, we don't evaluate the right operand
+	switch expr.Operator.TokenType {
+	case token.OR:
 		if i.isTruthy(left) {
 			return left, nil
 		}
-	} else {
+	case token.QUESTION_QUESTION:
+		if left != nil {
+			return left, nil
+		}
+	default:
 		if !i.isTruthy(left) {
 			return left, nil
 		}
@@ -319,51 +585,294 @@ func (i *Interpreter) VisitLogicalExpr(expr *syntax.Logical) (any, error) {
 	return i.evaluate(expr.Right)
 }
 
-func (i *Interpreter) VisitCallExpr(expr *syntax.Call) (any, error) {
-	callee, err := i.evaluate(expr.Callee)
+func (i *Interpreter) VisitConditionalExpr(expr *syntax.Conditional) (any, error) {
+	condition, err := i.evaluate(expr.Condition)
 	if err != nil {
 		return nil, err
 	}
+	// only the chosen branch is evaluated
+	if i.isTruthy(condition) {
+		return i.evaluate(expr.ThenBranch)
+	}
+	return i.evaluate(expr.ElseBranch)
+}
+
+func (i *Interpreter) VisitCallExpr(expr *syntax.Call) (any, error) {
+	val, _, err := i.call(expr)
+	return val, err
+}
+
+// a call that is a link of a chain, shorted is true when an optional link
+// in the chain was nil and the call was skipped
+func (i *Interpreter) call(expr *syntax.Call) (val any, shorted bool, err error) {
+	function, args, named, err := i.evaluateCall(expr)
+	if err != nil || function == nil {
+		return nil, function == nil && err == nil, err
+	}
+	val, err = i.invoke(expr, function, args, named)
+	return val, false, err
+}
 
-	// evaluate all arguments expressions
+// evaluates the callee and the arguments of a call, and checks that they
+// can be called together. the function is nil without an error when the
+// chain of the callee is cut short by an optional link
+func (i *Interpreter) evaluateCall(expr *syntax.Call) (Callable, []any, []namedArgument, error) {
+	callee, shorted, err := i.evaluateLink(expr.Callee)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// the arguments aren't evaluated either
+	if shorted || (expr.Optional && callee == nil) {
+		return nil, nil, nil, nil
+	}
+
+	// evaluate all arguments expressions, spread ones expand in place so
+	// the arity is only known once they are all evaluated
 	args := []any{}
 	for _, arg := range expr.Arguments {
+		spread, isSpread := arg.(*syntax.Spread)
+		if isSpread {
+			arg = spread.Expression
+		}
 		val, err := i.evaluate(arg)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
+		}
+		if !isSpread {
+			args = append(args, val)
+			continue
+		}
+		values, err := i.spread(spread.Ellipsis, val)
+		if err != nil {
+			return nil, nil, nil, err
 		}
-		args = append(args, val)
+		args = append(args, values...)
+	}
+	named := []namedArgument{}
+	for _, arg := range expr.NamedArguments {
+		val, err := i.evaluate(arg.Value)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		named = append(named, namedArgument{Name: arg.Name, Value: val})
 	}
 
 	// check if the callee is actually a function, since we have defined
 	// "primary" to be callee which includes strings, numbers, etc.
 	function, ok := callee.(Callable)
 	if !ok {
-		return nil, NewRuntimeError(expr.Paren, "Callee must be a function")
+		return nil, nil, nil, NewRuntimeError(expr.Paren, "Callee must be a function")
 	}
+	_, isUserFunction := function.(*Function)
 
-	// arguments count must match the function's arity (expected count)
-	if len(args) != function.Arity() {
-		return nil, NewRuntimeError(expr.Paren, fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(args)))
+	// arguments count must match the function's arity, named arguments
+	// are matched up with the parameters when the function binds them
+	if len(named) == 0 {
+		if err := checkArity(expr.Paren, function, len(args)); err != nil {
+			return nil, nil, nil, err
+		}
+	} else if !isUserFunction {
+		return nil, nil, nil, NewRuntimeError(expr.Paren, "Only functions declared with 'fun' take named arguments")
 	}
+	return function, args, named, nil
+}
 
-	return function.Call(i, args)
+// calls a callable with evaluated arguments, keeping track of the call depth
+func (i *Interpreter) invoke(expr *syntax.Call, function Callable, args []any, named []namedArgument) (any, error) {
+	fn, isUserFunction := function.(*Function)
+
+	if i.counters.callDepth >= maxCallDepth {
+		return nil, NewRuntimeError(expr.Paren, "Stack overflow.")
+	}
+	i.counters.callDepth++
+	defer func() {
+		i.counters.callDepth--
+	}()
+
+	if !isUserFunction {
+		val, err := function.Call(i, args)
+		if err != nil {
+			// natives don't know where they were called from, so their
+			// errors are reported at the call site, unless they come from
+			// lox code e.g. a generator run by next()
+			if _, ok := err.(*RuntimeError); !ok && !errors.Is(err, ErrStepLimit) {
+				return nil, NewRuntimeError(expr.Paren, err.Error())
+			}
+		}
+		return val, err
+	}
+	if i.tracer == nil {
+		return fn.call(i, expr.Paren, args, named)
+	}
+
+	name := fn.Declaration.Name.Lexeme
+	tracedArgs := make([]string, 0, len(args)+len(named))
+	for _, arg := range args {
+		tracedArgs = append(tracedArgs, i.stringify(arg))
+	}
+	for _, arg := range named {
+		tracedArgs = append(tracedArgs, arg.Name.Lexeme+": "+i.stringify(arg.Value))
+	}
+	i.tracer.enter(name, expr.Paren.LineNumber, tracedArgs)
+	val, err := fn.call(i, expr.Paren, args, named)
+	i.tracer.exit(name, expr.Paren.LineNumber, i.stringify(val))
+	return val, err
+}
+
+func (i *Interpreter) VisitIndexExpr(expr *syntax.Index) (any, error) {
+	val, _, err := i.index(expr)
+	return val, err
+}
+
+func (i *Interpreter) VisitIndexSetExpr(expr *syntax.IndexSet) (any, error) {
+	object, err := i.evaluate(expr.Object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.evaluate(expr.Index)
+	if err != nil {
+		return nil, err
+	}
+	// strings are immutable
+	list, ok := object.(*List)
+	if !ok {
+		return nil, NewRuntimeError(expr.Bracket, "Only list elements can be assigned")
+	}
+
+	if expr.Operator.TokenType == token.EQUAL {
+		value, err := i.evaluate(expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		idx, err := i.checkIndex(expr.Bracket, index, len(list.Elements))
+		if err != nil {
+			return nil, err
+		}
+		list.Elements[idx] = value
+		return value, nil
+	}
+
+	idx, err := i.checkIndex(expr.Bracket, index, len(list.Elements))
+	if err != nil {
+		return nil, err
+	}
+	current := list.Elements[idx]
+	var result any
+	if expr.Value == nil {
+		result, err = i.increment(expr.Operator, current)
+	} else {
+		var value any
+		value, err = i.evaluate(expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		result, err = i.compound(expr.Operator, current, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// evaluating the value may have shrunk the list
+	if idx, err = i.checkIndex(expr.Bracket, index, len(list.Elements)); err != nil {
+		return nil, err
+	}
+	list.Elements[idx] = result
+
+	if expr.Value == nil && !expr.Prefix {
+		return current, nil
+	}
+	return result, nil
+}
+
+// an index that is a link of a chain, like call
+func (i *Interpreter) index(expr *syntax.Index) (val any, shorted bool, err error) {
+	object, shorted, err := i.evaluateLink(expr.Object)
+	if err != nil || shorted {
+		return nil, shorted, err
+	}
+	if expr.Optional && object == nil {
+		return nil, true, nil
+	}
+	index, err := i.evaluate(expr.Index)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if list, ok := object.(*List); ok {
+		idx, err := i.checkIndex(expr.Bracket, index, len(list.Elements))
+		if err != nil {
+			return nil, false, err
+		}
+		return list.Elements[idx], false, nil
+	}
+	str, ok := object.(string)
+	if !ok {
+		return nil, false, NewRuntimeError(expr.Bracket, "Only strings and lists can be indexed")
+	}
+	// strings are indexed by code point rather than by byte
+	chars := []rune(str)
+	idx, err := i.checkIndex(expr.Bracket, index, len(chars))
+	if err != nil {
+		return nil, false, err
+	}
+	return string(chars[idx]), false, nil
+}
+
+// evaluates the callee of a call or the object of an index or a property
+// access. a nil before "?." skips the rest of the chain it is in, so
+// a?.[0][1] is nil when a is, while parentheses end a chain: (a?.[0])[1] fails
+func (i *Interpreter) evaluateLink(expr syntax.Expr) (val any, shorted bool, err error) {
+	switch e := expr.(type) {
+	case *syntax.Call:
+		return i.call(e)
+	case *syntax.Index:
+		return i.index(e)
+	case *syntax.Get:
+		return i.get(e)
+	}
+	val, err = i.evaluate(expr)
+	return val, false, err
+}
+
+// validates that index is a whole number within [0, length)
+func (i *Interpreter) checkIndex(bracket token.Token, index any, length int) (int, error) {
+	val, ok := index.(int64)
+	if !ok {
+		return 0, NewRuntimeError(bracket, "Index must be an integer")
+	}
+	if val < 0 || val >= int64(length) {
+		return 0, NewRuntimeError(bracket, "Index out of range")
+	}
+	return int(val), nil
+}
+
+func (i *Interpreter) VisitInterpolationExpr(expr *syntax.Interpolation) (any, error) {
+	var builder strings.Builder
+	for _, part := range expr.Parts {
+		val, err := i.evaluate(part)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(i.stringify(val))
+	}
+	return builder.String(), nil
 }
 
 // executes binary expressions with + operator depending on
 // the type i.e. concatenate for strings, add for numbers
-func (i *Interpreter) executeAdd(left any, right any) any {
+// a string plus any other value stays an error, like in the reference lox
+// test suite, interpolation ("total: ${n}") is how values are put in strings
+func (i *Interpreter) executeAdd(operator token.Token, left any, right any) (any, error) {
 	switch l := left.(type) {
-	case float64:
-		if r, ok := right.(float64); ok {
-			return l + r
+	case int64, float64, *big.Int, *big.Rat:
+		if isNumber(right) {
+			return i.arithmetic(operator, left, right)
 		}
 	case string:
 		if r, ok := right.(string); ok {
-			return l + r
+			return l + r, nil
 		}
 	}
-	return fmt.Errorf("Invalid operands type for + operation")
+	return nil, NewRuntimeError(operator, "Operands must be two numbers or two strings")
 }
 
 func (i *Interpreter) isEqual(a any, b any) bool {
@@ -373,45 +882,47 @@ func (i *Interpreter) isEqual(a any, b any) bool {
 	if a == nil {
 		return false
 	}
+	if equal, ok := numbersEqual(a, b); ok {
+		return equal
+	}
 
 	return a == b
 }
 
 func (i *Interpreter) stringify(value any) string {
+	return i.stringifyValue(value, nil)
+}
+
+// printing tracks the lists it is inside of, a list that contains itself
+// is printed as [...] where it repeats
+func (i *Interpreter) stringifyValue(value any, printing map[*List]bool) string {
 	if value == nil {
 		return "nil"
 	}
 	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
 	case float64:
-		text := fmt.Sprintf("%g", v)
-		text = strings.TrimSuffix(text, ".0")
-		return text
+		return formatFloat(v)
+	case *big.Int:
+		return v.String()
+	case *big.Rat:
+		return formatDecimal(v)
+	case *List:
+		if printing[v] {
+			return "[...]"
+		}
+		if printing == nil {
+			printing = map[*List]bool{}
+		}
+		printing[v] = true
+		defer delete(printing, v)
+		elements := make([]string, len(v.Elements))
+		for idx, element := range v.Elements {
+			elements[idx] = i.stringifyValue(element, printing)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
 	}
 
 	return fmt.Sprintf("%v", value)
 }
-
-// for unary mathematical evaluation
-//
-// this raises an evaluation error when operand with wrong type is encountered.
-// expected type is number
-func (i *Interpreter) checkNumberOperand(operator token.Token, operand any) (float64, error) {
-	val, ok := operand.(float64)
-	if !ok {
-		return 0, NewRuntimeError(operator, "Operator must be a number")
-	}
-	return val, nil
-}
-
-// for binary mathematical evaluation
-//
-// this raises an evaluation error when operands with wrong types are encountered
-// expected types are number
-func (i *Interpreter) checkNumberOperands(operator token.Token, left any, right any) (leftVal float64, rightVal float64, err error) {
-	leftVal, lOk := left.(float64)
-	rightVal, rOk := right.(float64)
-	if !lOk || !rOk {
-		return 0, 0, NewRuntimeError(operator, "Operator must be a number")
-	}
-	return leftVal, rightVal, nil
-}
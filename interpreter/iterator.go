@@ -0,0 +1,123 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// values that can be looped over with for-in implement Iterable, every
+// loop asks for a new Iterator so the same value can be iterated again.
+// there are no maps yet, a map type would implement it with its keys as Key
+type Iterable interface {
+	Iterator() Iterator
+}
+
+type Iterator interface {
+	// advances to the next element, false once there are none left
+	Next() (bool, error)
+	// key of the current element e.g. its position
+	Key() any
+	// the current element
+	Value() any
+}
+
+// half-open integer range start..end, produced by the ".." operator
+type Range struct {
+	Start int64
+	End   int64
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d..%d", r.Start, r.End)
+}
+
+func (r Range) Iterator() Iterator {
+	return &rangeIterator{r: r, index: -1}
+}
+
+type rangeIterator struct {
+	r     Range
+	index int64
+}
+
+func (it *rangeIterator) Next() (bool, error) {
+	// compared by value, the length End-Start could overflow
+	if it.r.Start+it.index+1 >= it.r.End {
+		return false, nil
+	}
+	it.index++
+	return true, nil
+}
+
+func (it *rangeIterator) Key() any {
+	return it.index
+}
+
+func (it *rangeIterator) Value() any {
+	return it.r.Start + it.index
+}
+
+// iterates over the characters of a string, keyed by their index
+type stringIterator struct {
+	runes []rune
+	index int64
+}
+
+func (it *stringIterator) Next() (bool, error) {
+	if it.index+1 >= int64(len(it.runes)) {
+		return false, nil
+	}
+	it.index++
+	return true, nil
+}
+
+func (it *stringIterator) Key() any {
+	return it.index
+}
+
+func (it *stringIterator) Value() any {
+	return string(it.runes[it.index])
+}
+
+// returns an iterator over the given value, tok is used to report
+// values that can't be iterated
+func (i *Interpreter) iterate(tok token.Token, value any) (Iterator, error) {
+	switch v := value.(type) {
+	case string:
+		return &stringIterator{runes: []rune(v), index: -1}, nil
+	case Iterable:
+		return v.Iterator(), nil
+	}
+	return nil, NewRuntimeError(tok, "Can only iterate over strings, lists, ranges and generators")
+}
+
+func (i *Interpreter) newRange(operator token.Token, start any, end any) (any, error) {
+	startVal, startOk := start.(int64)
+	endVal, endOk := end.(int64)
+	if !startOk || !endOk {
+		return nil, NewRuntimeError(operator, "Range bounds must be integers")
+	}
+	return Range{Start: startVal, End: endVal}, nil
+}
+
+// stops an iterator that may be abandoned before its end, e.g. by leaving
+// a loop early or by an error, a generator would stay suspended otherwise
+func closeIterator(iterator Iterator) {
+	if closer, ok := iterator.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// advances the iterator, errors that don't come from lox code, like
+// resuming a running generator, are reported at tok
+func advance(tok token.Token, iterator Iterator) (bool, error) {
+	ok, err := iterator.Next()
+	if err != nil {
+		if _, isRuntime := err.(*RuntimeError); !isRuntime && !errors.Is(err, ErrStepLimit) {
+			return false, NewRuntimeError(tok, err.Error())
+		}
+	}
+	return ok, err
+}
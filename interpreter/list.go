@@ -0,0 +1,38 @@
+package interpreter
+
+// ordered, mutable sequence of values, created by list literals and
+// variadic parameters
+type List struct {
+	Elements []any
+}
+
+func NewList(elements []any) *List {
+	return &List{Elements: elements}
+}
+
+func (l *List) Iterator() Iterator {
+	return &listIterator{list: l, index: -1}
+}
+
+// iterates over the elements, keyed by their index
+type listIterator struct {
+	list  *List
+	index int64
+}
+
+func (it *listIterator) Next() (bool, error) {
+	// the length is checked every time, the loop body can change the list
+	if it.index+1 >= int64(len(it.list.Elements)) {
+		return false, nil
+	}
+	it.index++
+	return true, nil
+}
+
+func (it *listIterator) Key() any {
+	return it.index
+}
+
+func (it *listIterator) Value() any {
+	return it.list.Elements[it.index]
+}
@@ -0,0 +1,201 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// defines the built-in functions available in the global scope
+func (i *Interpreter) defineNatives() {
+	i.globals.Define("clock", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			return time.Now().UnixNano() / 1e6, nil
+		},
+		arity: 0,
+	})
+
+	// len(string) or len(list), counts code points rather than bytes
+	i.globals.Define("len", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case string:
+				return int64(utf8.RuneCountInString(v)), nil
+			case *List:
+				return int64(len(v.Elements)), nil
+			}
+			return nil, fmt.Errorf("len() expects a string or a list")
+		},
+		arity: 1,
+	})
+
+	// int(value), converts other numbers (truncating towards zero) and numeric strings to integers
+	i.globals.Define("int", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case int64:
+				return v, nil
+			case float64:
+				if math.IsNaN(v) || v >= math.MaxInt64 || v < math.MinInt64 {
+					return nil, fmt.Errorf("int() can't convert %s to an integer", i.stringify(v))
+				}
+				return int64(v), nil
+			case *big.Int:
+				if !v.IsInt64() {
+					return nil, fmt.Errorf("int() can't convert %s to an integer", i.stringify(v))
+				}
+				return v.Int64(), nil
+			case *big.Rat:
+				truncated := new(big.Int).Quo(v.Num(), v.Denom())
+				if !truncated.IsInt64() {
+					return nil, fmt.Errorf("int() can't convert %s to an integer", i.stringify(v))
+				}
+				return truncated.Int64(), nil
+			case string:
+				val, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("int() can't convert \"%s\" to an integer", v)
+				}
+				return val, nil
+			}
+			return nil, fmt.Errorf("int() expects a number or a string")
+		},
+		arity: 1,
+	})
+
+	// float(value), converts other numbers and numeric strings to floats,
+	// big integers and decimals are rounded to the nearest float
+	i.globals.Define("float", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case int64, float64, *big.Int, *big.Rat:
+				return toFloat(v), nil
+			case string:
+				val, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+				if err != nil {
+					return nil, fmt.Errorf("float() can't convert \"%s\" to a float", v)
+				}
+				return val, nil
+			}
+			return nil, fmt.Errorf("float() expects a number or a string")
+		},
+		arity: 1,
+	})
+
+	// bigint(value), converts integers, whole decimals and numeric strings to big integers
+	i.globals.Define("bigint", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case int64:
+				return big.NewInt(v), nil
+			case *big.Int:
+				return v, nil
+			case *big.Rat:
+				if !v.IsInt() {
+					return nil, fmt.Errorf("bigint() can't convert %s without losing its fraction", i.stringify(v))
+				}
+				return new(big.Int).Set(v.Num()), nil
+			case float64:
+				if math.IsInf(v, 0) || math.IsNaN(v) || v != math.Trunc(v) {
+					return nil, fmt.Errorf("bigint() can't convert %s exactly", i.stringify(v))
+				}
+				val, _ := big.NewFloat(v).Int(nil)
+				return val, nil
+			case string:
+				val, ok := new(big.Int).SetString(strings.TrimSpace(v), 10)
+				if !ok {
+					return nil, fmt.Errorf("bigint() can't convert \"%s\" to a big integer", v)
+				}
+				return val, nil
+			}
+			return nil, fmt.Errorf("bigint() expects a number or a string")
+		},
+		arity: 1,
+	})
+
+	// decimal(value), converts numbers and numeric strings to decimals. Floats
+	// are converted from their shortest representation so decimal(0.1) is 0.1d
+	i.globals.Define("decimal", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case int64, *big.Int, *big.Rat:
+				val, _ := toRat(v)
+				return val, nil
+			case float64:
+				if math.IsInf(v, 0) || math.IsNaN(v) {
+					return nil, fmt.Errorf("decimal() can't convert %s", i.stringify(v))
+				}
+				val, _ := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+				return val, nil
+			case string:
+				text := strings.TrimSpace(v)
+				if strings.ContainsAny(text, "eE/") {
+					return nil, fmt.Errorf("decimal() expects plain digits e.g. \"12.50\"")
+				}
+				val, ok := new(big.Rat).SetString(text)
+				if !ok {
+					return nil, fmt.Errorf("decimal() can't convert \"%s\" to a decimal", v)
+				}
+				return val, nil
+			}
+			return nil, fmt.Errorf("decimal() expects a number or a string")
+		},
+		arity: 1,
+	})
+
+	// next(generator), runs a generator up to its next yield and returns the
+	// yielded value, nil once the generator has finished, see done()
+	i.globals.Define("next", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			g, ok := args[0].(*Generator)
+			if !ok {
+				return nil, fmt.Errorf("next() expects a generator")
+			}
+			ok, err := g.Next()
+			if err != nil || !ok {
+				return nil, err
+			}
+			return g.Value(), nil
+		},
+		arity: 1,
+	})
+
+	// done(generator), true once the generator has finished, which tells a
+	// yielded nil apart from the nil next() returns at the end
+	i.globals.Define("done", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			g, ok := args[0].(*Generator)
+			if !ok {
+				return nil, fmt.Errorf("done() expects a generator")
+			}
+			return g.Done(), nil
+		},
+		arity: 1,
+	})
+
+	// assert(condition, message)
+	i.globals.Define("assert", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			if !i.isTruthy(args[0]) {
+				return nil, fmt.Errorf("Assertion failed: %s", i.stringify(args[1]))
+			}
+			return nil, nil
+		},
+		arity: 2,
+	})
+
+	// assertEqual(actual, expected)
+	i.globals.Define("assertEqual", &NativeCallable{
+		fn: func(args []any) (any, error) {
+			if !i.isEqual(args[0], args[1]) {
+				return nil, fmt.Errorf("Assertion failed: expected %s but got %s", i.stringify(args[1]), i.stringify(args[0]))
+			}
+			return nil, nil
+		},
+		arity: 2,
+	})
+}
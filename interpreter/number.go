@@ -0,0 +1,564 @@
+package interpreter
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// numbers come in four kinds:
+//   - integers (int64)
+//   - floats (float64)
+//   - big integers (*big.Int), arbitrary precision integers e.g. 123n
+//   - decimals (*big.Rat), exact decimal fractions e.g. 0.1d
+//
+// promotion rules for binary operators:
+//   - operands of the same kind give the same kind, "/" truncates for integers
+//   - integers, big integers and decimals are promoted to the widest of them
+//     i.e. integer -> big integer -> decimal, which never loses precision
+//   - integers are converted to floats when mixed with a float
+//   - mixing a float with a big integer or a decimal is a runtime error, one
+//     of them has to be converted explicitly with float(), bigint() or decimal()
+//   - integer overflow and division by zero are runtime errors, so is a
+//     decimal division whose result can't be written with finitely many digits
+//
+// comparisons and equality are exact across all kinds
+func (i *Interpreter) arithmetic(operator token.Token, left any, right any) (any, error) {
+	leftVal, rightVal, err := i.checkNumberOperands(operator, left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch l := leftVal.(type) {
+	case int64:
+		return i.intArithmetic(operator, l, rightVal.(int64))
+	case *big.Int:
+		return i.bigIntArithmetic(operator, l, rightVal.(*big.Int))
+	case *big.Rat:
+		return i.decimalArithmetic(operator, l, rightVal.(*big.Rat))
+	}
+	return floatArithmetic(operator, leftVal.(float64), rightVal.(float64)), nil
+}
+
+func (i *Interpreter) intArithmetic(operator token.Token, l int64, r int64) (any, error) {
+	switch operator.TokenType {
+	case token.PLUS:
+		sum := l + r
+		if (r > 0 && sum < l) || (r < 0 && sum > l) {
+			return nil, NewRuntimeError(operator, "Integer overflow")
+		}
+		return sum, nil
+	case token.MINUS:
+		diff := l - r
+		if (r > 0 && diff > l) || (r < 0 && diff < l) {
+			return nil, NewRuntimeError(operator, "Integer overflow")
+		}
+		return diff, nil
+	case token.STAR:
+		product := l * r
+		if l != 0 && (product/l != r || (l == -1 && r == math.MinInt64)) {
+			return nil, NewRuntimeError(operator, "Integer overflow")
+		}
+		return product, nil
+	case token.SLASH:
+		if r == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero")
+		}
+		if l == math.MinInt64 && r == -1 {
+			return nil, NewRuntimeError(operator, "Integer overflow")
+		}
+		return l / r, nil
+	case token.PERCENT:
+		if r == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero")
+		}
+		return l % r, nil
+	}
+	return nil, NewRuntimeError(operator, "Unsupported integer operator")
+}
+
+func floatArithmetic(operator token.Token, l float64, r float64) any {
+	switch operator.TokenType {
+	case token.PLUS:
+		return l + r
+	case token.MINUS:
+		return l - r
+	case token.STAR:
+		return l * r
+	case token.SLASH:
+		return l / r
+	case token.PERCENT:
+		return math.Mod(l, r)
+	}
+	return nil
+}
+
+func (i *Interpreter) bigIntArithmetic(operator token.Token, l *big.Int, r *big.Int) (any, error) {
+	switch operator.TokenType {
+	case token.PLUS:
+		return new(big.Int).Add(l, r), nil
+	case token.MINUS:
+		return new(big.Int).Sub(l, r), nil
+	case token.STAR:
+		return new(big.Int).Mul(l, r), nil
+	case token.SLASH:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero")
+		}
+		return new(big.Int).Quo(l, r), nil
+	case token.PERCENT:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero")
+		}
+		return new(big.Int).Rem(l, r), nil
+	}
+	return nil, NewRuntimeError(operator, "Unsupported big integer operator")
+}
+
+func (i *Interpreter) decimalArithmetic(operator token.Token, l *big.Rat, r *big.Rat) (any, error) {
+	switch operator.TokenType {
+	case token.PLUS:
+		return new(big.Rat).Add(l, r), nil
+	case token.MINUS:
+		return new(big.Rat).Sub(l, r), nil
+	case token.STAR:
+		return new(big.Rat).Mul(l, r), nil
+	case token.SLASH:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero")
+		}
+		quotient := new(big.Rat).Quo(l, r)
+		if _, ok := decimalDigits(quotient); !ok {
+			return nil, NewRuntimeError(operator, "Decimal division result has infinitely many digits")
+		}
+		return quotient, nil
+	case token.PERCENT:
+		if r.Sign() == 0 {
+			return nil, NewRuntimeError(operator, "Division by zero")
+		}
+		// l - r * trunc(l / r), has the sign of l like the integer remainder
+		quotient := new(big.Rat).Quo(l, r)
+		truncated := new(big.Rat).SetInt(new(big.Int).Quo(quotient.Num(), quotient.Denom()))
+		return new(big.Rat).Sub(l, truncated.Mul(truncated, r)), nil
+	}
+	return nil, NewRuntimeError(operator, "Unsupported decimal operator")
+}
+
+// largest exponent or shift count allowed for big integers and decimals,
+// bigger ones would allocate an absurd amount of digits
+const maxBigExponent = 1 << 16
+
+// evaluates base ** exponent
+//
+// integer kinds raised to a non-negative integer give the same kind (exact),
+// a negative integer exponent on an integer or any float operand gives a float.
+// Decimals can also be raised to negative integers as long as the result is finite
+func (i *Interpreter) power(operator token.Token, left any, right any) (any, error) {
+	if !isNumber(left) || !isNumber(right) {
+		return nil, NewRuntimeError(operator, "Operator must be a number")
+	}
+
+	exponent, isIntegerExponent := right.(int64)
+	if bigExponent, ok := right.(*big.Int); ok {
+		if !bigExponent.IsInt64() {
+			return nil, NewRuntimeError(operator, "Exponent too large")
+		}
+		exponent, isIntegerExponent = bigExponent.Int64(), true
+	}
+
+	switch base := left.(type) {
+	case int64:
+		if _, isBig := right.(*big.Int); isBig && exponent >= 0 {
+			return i.power(operator, big.NewInt(base), right)
+		}
+		if isIntegerExponent && exponent >= 0 {
+			return intPower(operator, base, exponent)
+		}
+	case *big.Int:
+		if isIntegerExponent {
+			if exponent < 0 || exponent > maxBigExponent {
+				return nil, NewRuntimeError(operator, "Exponent must be between 0 and 65536")
+			}
+			return new(big.Int).Exp(base, big.NewInt(exponent), nil), nil
+		}
+	case *big.Rat:
+		if isIntegerExponent {
+			if exponent < -maxBigExponent || exponent > maxBigExponent {
+				return nil, NewRuntimeError(operator, "Exponent must be between -65536 and 65536")
+			}
+			abs := big.NewInt(max(exponent, -exponent))
+			num := new(big.Int).Exp(base.Num(), abs, nil)
+			denom := new(big.Int).Exp(base.Denom(), abs, nil)
+			if exponent < 0 {
+				if num.Sign() == 0 {
+					return nil, NewRuntimeError(operator, "Division by zero")
+				}
+				num, denom = denom, num
+			}
+			result := new(big.Rat).SetFrac(num, denom)
+			if _, ok := decimalDigits(result); !ok {
+				return nil, NewRuntimeError(operator, "Decimal division result has infinitely many digits")
+			}
+			return result, nil
+		}
+	}
+
+	// everything else is computed with floats, which must not mix with bigints or decimals
+	_, leftIsFloat := left.(float64)
+	_, rightIsFloat := right.(float64)
+	_, leftIsInt := left.(int64)
+	if leftIsFloat || rightIsFloat {
+		if _, _, err := i.checkNumberOperands(operator, left, right); err != nil {
+			return nil, err
+		}
+	} else if !leftIsInt || !isIntegerExponent {
+		return nil, NewRuntimeError(operator, "Exponent must be an integer")
+	}
+	return math.Pow(toFloat(left), toFloat(right)), nil
+}
+
+func intPower(operator token.Token, base int64, exponent int64) (any, error) {
+	switch {
+	case exponent == 0 || base == 1:
+		return int64(1), nil
+	case base == 0:
+		return int64(0), nil
+	case base == -1:
+		if exponent%2 == 0 {
+			return int64(1), nil
+		}
+		return int64(-1), nil
+	case exponent >= 64:
+		// |base| >= 2 so the result needs at least 64 bits
+		return nil, NewRuntimeError(operator, "Integer overflow")
+	}
+	result := new(big.Int).Exp(big.NewInt(base), big.NewInt(exponent), nil)
+	if !result.IsInt64() {
+		return nil, NewRuntimeError(operator, "Integer overflow")
+	}
+	return result.Int64(), nil
+}
+
+// evaluates the bitwise operators & | ^ << and >> on integers and big integers
+func (i *Interpreter) bitwise(operator token.Token, left any, right any) (any, error) {
+	leftVal, rightVal, err := i.checkIntegerOperands(operator, left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	if l, ok := leftVal.(int64); ok {
+		r := rightVal.(int64)
+		switch operator.TokenType {
+		case token.AMPERSAND:
+			return l & r, nil
+		case token.PIPE:
+			return l | r, nil
+		case token.CARET:
+			return l ^ r, nil
+		case token.LESS_LESS, token.GREATER_GREATER:
+			if r < 0 || r > 63 {
+				return nil, NewRuntimeError(operator, "Shift count must be between 0 and 63")
+			}
+			if operator.TokenType == token.LESS_LESS {
+				return l << r, nil
+			}
+			return l >> r, nil
+		}
+	}
+
+	l, r := leftVal.(*big.Int), rightVal.(*big.Int)
+	switch operator.TokenType {
+	case token.AMPERSAND:
+		return new(big.Int).And(l, r), nil
+	case token.PIPE:
+		return new(big.Int).Or(l, r), nil
+	case token.CARET:
+		return new(big.Int).Xor(l, r), nil
+	case token.LESS_LESS, token.GREATER_GREATER:
+		if r.Sign() < 0 || r.Cmp(big.NewInt(maxBigExponent)) > 0 {
+			return nil, NewRuntimeError(operator, "Shift count must be between 0 and 65536")
+		}
+		if operator.TokenType == token.LESS_LESS {
+			return new(big.Int).Lsh(l, uint(r.Uint64())), nil
+		}
+		return new(big.Int).Rsh(l, uint(r.Uint64())), nil
+	}
+	return nil, NewRuntimeError(operator, "Unsupported bitwise operator")
+}
+
+func (i *Interpreter) bitwiseNot(operator token.Token, operand any) (any, error) {
+	switch v := operand.(type) {
+	case int64:
+		return ^v, nil
+	case *big.Int:
+		return new(big.Int).Not(v), nil
+	}
+	return nil, NewRuntimeError(operator, "Operand must be an integer")
+}
+
+// for bitwise evaluation
+//
+// this raises an evaluation error unless both operands are integers or big integers,
+// returns both as int64 or, if either one is a big integer, as *big.Int
+func (i *Interpreter) checkIntegerOperands(operator token.Token, left any, right any) (leftVal any, rightVal any, err error) {
+	switch l := left.(type) {
+	case int64:
+		switch r := right.(type) {
+		case int64:
+			return l, r, nil
+		case *big.Int:
+			return big.NewInt(l), r, nil
+		}
+	case *big.Int:
+		switch r := right.(type) {
+		case int64:
+			return l, big.NewInt(r), nil
+		case *big.Int:
+			return l, r, nil
+		}
+	}
+	return nil, nil, NewRuntimeError(operator, "Operands must be integers")
+}
+
+// evaluates <, <=, > and >= on two numbers
+func (i *Interpreter) compare(operator token.Token, left any, right any) (bool, error) {
+	if !isNumber(left) || !isNumber(right) {
+		return false, NewRuntimeError(operator, "Operator must be a number")
+	}
+	switch l := left.(type) {
+	case int64:
+		if r, ok := right.(int64); ok {
+			return compareOrdered(operator, l, r), nil
+		}
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compareOrdered(operator, l, r), nil
+		}
+	}
+
+	leftRat, lOk := toRat(left)
+	rightRat, rOk := toRat(right)
+	if !lOk || !rOk {
+		// infinities and NaN have no exact value, compare them as floats
+		return compareOrdered(operator, toFloat(left), toFloat(right)), nil
+	}
+	return compareOrdered(operator, leftRat.Cmp(rightRat), 0), nil
+}
+
+func compareOrdered[T cmp.Ordered](operator token.Token, l T, r T) bool {
+	switch operator.TokenType {
+	case token.GREATER:
+		return l > r
+	case token.GREATER_EQUAL:
+		return l >= r
+	case token.LESS:
+		return l < r
+	case token.LESS_EQUAL:
+		return l <= r
+	}
+	return false
+}
+
+func (i *Interpreter) negate(operator token.Token, operand any) (any, error) {
+	switch v := operand.(type) {
+	case int64:
+		if v == math.MinInt64 {
+			return nil, NewRuntimeError(operator, "Integer overflow")
+		}
+		return -v, nil
+	case float64:
+		return -v, nil
+	case *big.Int:
+		return new(big.Int).Neg(v), nil
+	case *big.Rat:
+		return new(big.Rat).Neg(v), nil
+	}
+	return nil, NewRuntimeError(operator, "Operator must be a number")
+}
+
+// numbers of different kinds are equal if they have the same value e.g. 1 == 1.0
+func numbersEqual(a any, b any) (equal bool, ok bool) {
+	if !isNumber(a) || !isNumber(b) {
+		return false, false
+	}
+	switch l := a.(type) {
+	case int64:
+		if r, ok := b.(int64); ok {
+			return l == r, true
+		}
+	case float64:
+		if r, ok := b.(float64); ok {
+			return l == r, true
+		}
+	}
+
+	leftRat, lOk := toRat(a)
+	rightRat, rOk := toRat(b)
+	if !lOk || !rOk {
+		return toFloat(a) == toFloat(b), true
+	}
+	return leftRat.Cmp(rightRat) == 0, true
+}
+
+func isNumber(value any) bool {
+	switch value.(type) {
+	case int64, float64, *big.Int, *big.Rat:
+		return true
+	}
+	return false
+}
+
+// exact value of a number, fails for infinities and NaN
+func toRat(value any) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	case *big.Int:
+		return new(big.Rat).SetInt(v), true
+	case *big.Rat:
+		return v, true
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, false
+		}
+		return new(big.Rat).SetFloat64(v), true
+	}
+	return nil, false
+}
+
+// nearest float to a number, may lose precision
+func toFloat(value any) float64 {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	case *big.Rat:
+		f, _ := v.Float64()
+		return f
+	}
+	return math.NaN()
+}
+
+// number of fractional digits needed to write a decimal exactly,
+// fails if its denominator has prime factors other than 2 and 5
+func decimalDigits(value *big.Rat) (int, bool) {
+	denominator := new(big.Int).Set(value.Denom())
+	twos, fives := 0, 0
+	two, five := big.NewInt(2), big.NewInt(5)
+	remainder := new(big.Int)
+	for {
+		quotient, rem := new(big.Int).QuoRem(denominator, two, remainder)
+		if rem.Sign() != 0 {
+			break
+		}
+		denominator = quotient
+		twos++
+	}
+	for {
+		quotient, rem := new(big.Int).QuoRem(denominator, five, remainder)
+		if rem.Sign() != 0 {
+			break
+		}
+		denominator = quotient
+		fives++
+	}
+	return max(twos, fives), denominator.IsInt64() && denominator.Int64() == 1
+}
+
+// decimals always show a fractional part, like floats
+func formatDecimal(value *big.Rat) string {
+	digits, _ := decimalDigits(value)
+	return value.FloatString(max(digits, 1))
+}
+
+// floats always show a fractional part or an exponent so
+// that they can be told apart from integers e.g. 1.0 and 1
+func formatFloat(v float64) string {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	abs := math.Abs(v)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	text := strconv.FormatFloat(v, 'f', -1, 64)
+	if !strings.Contains(text, ".") {
+		text += ".0"
+	}
+	return text
+}
+
+func numberKindName(value any) string {
+	switch value.(type) {
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case *big.Int:
+		return "bigint"
+	case *big.Rat:
+		return "decimal"
+	}
+	return "non-number"
+}
+
+// for binary mathematical evaluation
+//
+// this raises an evaluation error when operands with wrong types are encountered,
+// otherwise returns both operands promoted to the same kind of number
+func (i *Interpreter) checkNumberOperands(operator token.Token, left any, right any) (leftVal any, rightVal any, err error) {
+	if !isNumber(left) || !isNumber(right) {
+		return nil, nil, NewRuntimeError(operator, "Operator must be a number")
+	}
+
+	switch l := left.(type) {
+	case int64:
+		switch r := right.(type) {
+		case int64:
+			return l, r, nil
+		case float64:
+			return float64(l), r, nil
+		case *big.Int:
+			return big.NewInt(l), r, nil
+		case *big.Rat:
+			return new(big.Rat).SetInt64(l), r, nil
+		}
+	case float64:
+		switch r := right.(type) {
+		case int64:
+			return l, float64(r), nil
+		case float64:
+			return l, r, nil
+		}
+	case *big.Int:
+		switch r := right.(type) {
+		case int64:
+			return l, big.NewInt(r), nil
+		case *big.Int:
+			return l, r, nil
+		case *big.Rat:
+			return new(big.Rat).SetInt(l), r, nil
+		}
+	case *big.Rat:
+		switch r := right.(type) {
+		case int64:
+			return l, new(big.Rat).SetInt64(r), nil
+		case *big.Int:
+			return l, new(big.Rat).SetInt(r), nil
+		case *big.Rat:
+			return l, r, nil
+		}
+	}
+
+	// the only remaining combinations mix a float with a bigint or a decimal
+	return nil, nil, NewRuntimeError(operator, fmt.Sprintf("Cannot mix %s and %s operands, convert one of them explicitly",
+		numberKindName(left), numberKindName(right)))
+}
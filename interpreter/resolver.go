@@ -8,19 +8,32 @@ import (
 
 type Resolver struct {
 	interpreter     *Interpreter
-	scopes          []map[string]bool
+	scopes          []map[string]*binding
 	currentFunction FunctionType
+	warnings        bool
+}
+
+// what the resolver knows about a name declared in a local scope
+type binding struct {
+	defined  bool // false while its initializer is being resolved
+	constant bool
 }
 
 func NewResolver(interpreter *Interpreter) *Resolver {
 	return &Resolver{interpreter: interpreter, currentFunction: NONE}
 }
 
+// reports likely mistakes, like a match statement without a catch-all arm
+func (r *Resolver) EnableWarnings() {
+	r.warnings = true
+}
+
 type FunctionType int
 
 const (
 	NONE FunctionType = iota
 	FUNCTION
+	GENERATOR
 )
 
 func (r *Resolver) VisitBlockStmt(stmt *syntax.Block) (any, error) {
@@ -53,7 +66,7 @@ func (r *Resolver) resolveExpr(expr syntax.Expr) error {
 }
 
 func (r *Resolver) beginScope() {
-	r.scopes = append(r.scopes, make(map[string]bool))
+	r.scopes = append(r.scopes, make(map[string]*binding))
 }
 
 func (r *Resolver) endScope() {
@@ -62,40 +75,46 @@ func (r *Resolver) endScope() {
 }
 
 func (r *Resolver) VisitVarStmt(stmt *syntax.Var) (any, error) {
-	r.declare(stmt.Name)
+	if err := r.declare(stmt.Name); err != nil {
+		return nil, err
+	}
 	if stmt.Initializer != nil {
 		if err := r.resolveExpr(stmt.Initializer); err != nil {
 			return nil, err
 		}
 	}
 	r.define(stmt.Name)
+	if stmt.Constant && len(r.scopes) != 0 {
+		r.scopes[len(r.scopes)-1][stmt.Name.Lexeme].constant = true
+	}
 	return nil, nil
 }
 
-func (r *Resolver) declare(name token.Token) {
+func (r *Resolver) declare(name token.Token) error {
 	// if we are not in a scope, we don't need to declare
 	if len(r.scopes) == 0 {
-		return
+		return nil
 	}
 	// if the variable is already declared in the current scope, throw an error
 	// can't have two variables with the same name in the same local scope
 	if _, ok := r.scopes[len(r.scopes)-1][name.Lexeme]; ok {
-		errorHandler.ReportError(name.LineNumber, "", "Already variable with this name in this scope.")
+		return errorHandler.ReportError(name.LineNumber, "at '"+name.Lexeme+"'", "Already variable with this name in this scope.")
 	}
-	r.scopes[len(r.scopes)-1][name.Lexeme] = false
+	r.scopes[len(r.scopes)-1][name.Lexeme] = &binding{}
+	return nil
 }
 
 func (r *Resolver) define(name token.Token) {
 	if len(r.scopes) == 0 {
 		return
 	}
-	r.scopes[len(r.scopes)-1][name.Lexeme] = true
+	r.scopes[len(r.scopes)-1][name.Lexeme].defined = true
 }
 
 func (r *Resolver) VisitVariableExpr(expr *syntax.Variable) (any, error) {
 	if len(r.scopes) != 0 {
-		if defined, ok := r.scopes[len(r.scopes)-1][expr.Name.Lexeme]; ok && !defined {
-			err := errorHandler.ReportError(expr.Name.LineNumber, "", "Cannot read local variable in its own initializer.")
+		if b, ok := r.scopes[len(r.scopes)-1][expr.Name.Lexeme]; ok && !b.defined {
+			err := errorHandler.ReportError(expr.Name.LineNumber, "at '"+expr.Name.Lexeme+"'", "Cannot read local variable in its own initializer.")
 			return nil, err
 		}
 	}
@@ -114,19 +133,61 @@ func (r *Resolver) resolveLocal(expr syntax.Expr, name token.Token) {
 	}
 }
 
+// constants are only known statically in local scopes, global
+// ones are checked by the environment at runtime
+func (r *Resolver) checkAssignable(name token.Token) error {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if b, ok := r.scopes[i][name.Lexeme]; ok {
+			if b.constant {
+				return errorHandler.ReportError(name.LineNumber, "at '"+name.Lexeme+"'", "Cannot assign to a constant.")
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
 func (r *Resolver) VisitAssignExpr(expr *syntax.Assign) (any, error) {
 	if err := r.resolveExpr(expr.Value); err != nil {
 		return nil, err
 	}
+	if err := r.checkAssignable(expr.Name); err != nil {
+		return nil, err
+	}
+	r.resolveLocal(expr, expr.Name)
+	return nil, nil
+}
+
+func (r *Resolver) VisitCompoundAssignExpr(expr *syntax.CompoundAssign) (any, error) {
+	if err := r.resolveExpr(expr.Value); err != nil {
+		return nil, err
+	}
+	if err := r.checkAssignable(expr.Name); err != nil {
+		return nil, err
+	}
+	r.resolveLocal(expr, expr.Name)
+	return nil, nil
+}
+
+func (r *Resolver) VisitIncrementExpr(expr *syntax.Increment) (any, error) {
+	if err := r.checkAssignable(expr.Name); err != nil {
+		return nil, err
+	}
 	r.resolveLocal(expr, expr.Name)
 	return nil, nil
 }
 
 func (r *Resolver) VisitFunctionStmt(stmt *syntax.Function) (any, error) {
-	r.declare(stmt.Name)
+	if err := r.declare(stmt.Name); err != nil {
+		return nil, err
+	}
 	r.define(stmt.Name)
 
-	if err := r.resolveFunction(stmt, FUNCTION); err != nil {
+	functionType := FUNCTION
+	if stmt.Generator {
+		functionType = GENERATOR
+	}
+	if err := r.resolveFunction(stmt, functionType); err != nil {
 		return nil, err
 	}
 	return nil, nil
@@ -140,8 +201,16 @@ func (r *Resolver) resolveFunction(function *syntax.Function, functionType Funct
 	}()
 	r.beginScope()
 	for _, param := range function.Params {
-		r.declare(param)
-		r.define(param)
+		// a default value can refer to the parameters before it
+		if param.Default != nil {
+			if err := r.resolveExpr(param.Default); err != nil {
+				return err
+			}
+		}
+		if err := r.declare(param.Name); err != nil {
+			return err
+		}
+		r.define(param.Name)
 	}
 	if err := r.ResolveStmts(function.Body); err != nil {
 		return err
@@ -181,7 +250,27 @@ func (r *Resolver) VisitPrintStmt(stmt *syntax.Print) (any, error) {
 
 func (r *Resolver) VisitReturnStmt(stmt *syntax.Return) (any, error) {
 	if r.currentFunction == NONE {
-		err := errorHandler.ReportError(stmt.Keyword.LineNumber, "", "Cannot return from top-level code.")
+		err := errorHandler.ReportError(stmt.Keyword.LineNumber, "at 'return'", "Cannot return from top-level code.")
+		return nil, err
+	}
+	if stmt.Value != nil {
+		if r.currentFunction == GENERATOR {
+			err := errorHandler.ReportError(stmt.Keyword.LineNumber, "at 'return'", "Cannot return a value from a generator.")
+			return nil, err
+		}
+		if err := r.resolveExpr(stmt.Value); err != nil {
+			return nil, err
+		}
+		// nothing is left to do in the function after the call returns
+		_, stmt.TailCall = stmt.Value.(*syntax.Call)
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitYieldStmt(stmt *syntax.Yield) (any, error) {
+	// the parser turns every function containing a yield into a generator
+	if r.currentFunction == NONE {
+		err := errorHandler.ReportError(stmt.Keyword.LineNumber, "at 'yield'", "Cannot yield from top-level code.")
 		return nil, err
 	}
 	if stmt.Value != nil {
@@ -202,6 +291,173 @@ func (r *Resolver) VisitWhileStmt(stmt *syntax.While) (any, error) {
 	return nil, nil
 }
 
+func (r *Resolver) VisitForInStmt(stmt *syntax.ForIn) (any, error) {
+	// the iterable is evaluated once, outside of the loop's scope
+	if err := r.resolveExpr(stmt.Iterable); err != nil {
+		return nil, err
+	}
+
+	r.beginScope()
+	defer r.endScope()
+	if stmt.Key != nil {
+		if err := r.declare(*stmt.Key); err != nil {
+			return nil, err
+		}
+		r.define(*stmt.Key)
+	}
+	if err := r.declare(stmt.Value); err != nil {
+		return nil, err
+	}
+	r.define(stmt.Value)
+
+	if err := r.resolveStmt(stmt.Body); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitMatchStmt(stmt *syntax.Match) (any, error) {
+	if err := r.resolveExpr(stmt.Subject); err != nil {
+		return nil, err
+	}
+
+	exhaustive := false
+	for _, arm := range stmt.Arms {
+		// every arm gets a scope for the names its pattern binds
+		r.beginScope()
+		if err := r.resolvePattern(arm.Pattern); err != nil {
+			return nil, err
+		}
+		if err := r.resolveStmt(arm.Body); err != nil {
+			return nil, err
+		}
+		r.endScope()
+
+		switch arm.Pattern.(type) {
+		case *syntax.WildcardPattern, *syntax.BindingPattern:
+			exhaustive = true
+		}
+	}
+
+	// values are dynamically typed, so only a catch-all arm covers every case
+	if r.warnings && !exhaustive {
+		errorHandler.ReportWarning(stmt.Keyword.LineNumber, "at 'match'", "Match is not exhaustive, add a '_' arm to handle unmatched values.")
+	}
+	return nil, nil
+}
+
+func (r *Resolver) resolvePattern(pattern syntax.Pattern) error {
+	switch p := pattern.(type) {
+	case *syntax.LiteralPattern:
+		return r.resolveExpr(p.Value)
+	case *syntax.BindingPattern:
+		if err := r.declare(p.Name); err != nil {
+			return err
+		}
+		r.define(p.Name)
+	case *syntax.ListPattern:
+		for _, element := range p.Elements {
+			if err := r.resolvePattern(element); err != nil {
+				return err
+			}
+		}
+		if p.Rest != nil && p.Rest.Lexeme != "_" {
+			if err := r.declare(*p.Rest); err != nil {
+				return err
+			}
+			r.define(*p.Rest)
+		}
+	case *syntax.AlternativePattern:
+		for _, alternative := range p.Alternatives {
+			if err := r.resolvePattern(alternative); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) VisitDestructuringVarStmt(stmt *syntax.DestructuringVar) (any, error) {
+	names := patternNames(stmt.Pattern, nil)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		// globals can be redeclared, but not twice by the same pattern
+		if seen[name.Lexeme] {
+			return nil, errorHandler.ReportError(name.LineNumber, "at '"+name.Lexeme+"'", "Name bound more than once in the same pattern.")
+		}
+		seen[name.Lexeme] = true
+		if err := r.declare(name); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.resolveExpr(stmt.Initializer); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		r.define(name)
+		if stmt.Constant && len(r.scopes) != 0 {
+			r.scopes[len(r.scopes)-1][name.Lexeme].constant = true
+		}
+	}
+	return nil, nil
+}
+
+// names declared by a declaration pattern, in order
+func patternNames(pattern syntax.Pattern, names []token.Token) []token.Token {
+	switch p := pattern.(type) {
+	case *syntax.BindingPattern:
+		names = append(names, p.Name)
+	case *syntax.ListPattern:
+		for _, element := range p.Elements {
+			names = patternNames(element, names)
+		}
+		if p.Rest != nil && p.Rest.Lexeme != "_" {
+			names = append(names, *p.Rest)
+		}
+	}
+	return names
+}
+
+func (r *Resolver) VisitDestructuringAssignExpr(expr *syntax.DestructuringAssign) (any, error) {
+	if err := r.resolveExpr(expr.Value); err != nil {
+		return nil, err
+	}
+	return nil, r.resolveTarget(expr.Target)
+}
+
+// the variables of a target are resolved like the name of a plain assignment
+func (r *Resolver) resolveTarget(target syntax.Expr) error {
+	switch t := target.(type) {
+	case *syntax.Variable:
+		if err := r.checkAssignable(t.Name); err != nil {
+			return err
+		}
+		r.resolveLocal(t, t.Name)
+	case *syntax.List:
+		for _, element := range t.Elements {
+			if err := r.resolveTarget(element); err != nil {
+				return err
+			}
+		}
+	case *syntax.Spread:
+		return r.resolveTarget(t.Expression)
+	}
+	return nil
+}
+
+func (r *Resolver) VisitListExpr(expr *syntax.List) (any, error) {
+	for _, element := range expr.Elements {
+		if err := r.resolveExpr(element); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitSpreadExpr(expr *syntax.Spread) (any, error) {
+	return nil, r.resolveExpr(expr.Expression)
+}
+
 func (r *Resolver) VisitBinaryExpr(expr *syntax.Binary) (any, error) {
 	if err := r.resolveExpr(expr.Left); err != nil {
 		return nil, err
@@ -222,7 +478,46 @@ func (r *Resolver) VisitCallExpr(expr *syntax.Call) (any, error) {
 			return nil, err
 		}
 	}
+	for _, argument := range expr.NamedArguments {
+		if err := r.resolveExpr(argument.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *Resolver) VisitIndexExpr(expr *syntax.Index) (any, error) {
+	if err := r.resolveExpr(expr.Object); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.Index); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
 
+func (r *Resolver) VisitIndexSetExpr(expr *syntax.IndexSet) (any, error) {
+	if err := r.resolveExpr(expr.Object); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.Index); err != nil {
+		return nil, err
+	}
+	if expr.Value != nil {
+		if err := r.resolveExpr(expr.Value); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitInterpolationExpr(expr *syntax.Interpolation) (any, error) {
+	for _, part := range expr.Parts {
+		if err := r.resolveExpr(part); err != nil {
+			return nil, err
+		}
+	}
 	return nil, nil
 }
 
@@ -247,9 +542,41 @@ func (r *Resolver) VisitLogicalExpr(expr *syntax.Logical) (any, error) {
 	return nil, nil
 }
 
+func (r *Resolver) VisitConditionalExpr(expr *syntax.Conditional) (any, error) {
+	if err := r.resolveExpr(expr.Condition); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.ThenBranch); err != nil {
+		return nil, err
+	}
+	if err := r.resolveExpr(expr.ElseBranch); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 func (r *Resolver) VisitUnaryExpr(expr *syntax.Unary) (any, error) {
 	if err := r.resolveExpr(expr.Right); err != nil {
 		return nil, err
 	}
 	return nil, nil
 }
+
+func (r *Resolver) VisitEnumStmt(stmt *syntax.Enum) (any, error) {
+	seen := make(map[string]bool)
+	for _, member := range stmt.Members {
+		if seen[member.Lexeme] {
+			return nil, errorHandler.ReportError(member.LineNumber, "at '"+member.Lexeme+"'", "Already a member with this name in this enum.")
+		}
+		seen[member.Lexeme] = true
+	}
+	if err := r.declare(stmt.Name); err != nil {
+		return nil, err
+	}
+	r.define(stmt.Name)
+	return nil, nil
+}
+
+func (r *Resolver) VisitGetExpr(expr *syntax.Get) (any, error) {
+	return nil, r.resolveExpr(expr.Object)
+}
@@ -0,0 +1,32 @@
+package interpreter
+
+import "testing"
+
+func TestMatchExhaustivenessWarning(t *testing.T) {
+	tests := []struct {
+		source   string
+		warnings bool
+		expected string
+	}{
+		{"match (1) { 1 => print 1; }", true, "[line 1] Warning at 'match': Match is not exhaustive, add a '_' arm to handle unmatched values.\n"},
+		{"match (1) { 1 => print 1; }", false, ""},
+		{"match (1) { 1 => print 1; _ => print 2; }", true, ""},
+		{"match (1) { 1 => print 1; n => print n; }", true, ""},
+	}
+
+	output := captureReports(t)
+	for _, test := range tests {
+		output.Reset()
+		statements, _ := parse(t, test.source)
+		resolver := NewResolver(NewInterpreter())
+		if test.warnings {
+			resolver.EnableWarnings()
+		}
+		if err := resolver.ResolveStmts(statements); err != nil {
+			t.Fatal(err)
+		}
+		if output.String() != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.source, test.expected, output.String())
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hamdan-khan/interpreter/syntax"
+)
+
+type TraceFormat int
+
+const (
+	TRACE_TEXT TraceFormat = iota
+	TRACE_JSON
+)
+
+// Tracer logs executed statements, function calls and variable assignments
+// of a running program to the given writer
+type Tracer struct {
+	out    io.Writer
+	format TraceFormat
+	lines  map[syntax.Stmt]int // statement -> source line, recorded by the parser
+
+	// filters, an empty function set or a zero line bound means "everything"
+	functions map[string]bool
+	fromLine  int
+	toLine    int
+
+	// names of the user functions currently being executed
+	callStack []string
+}
+
+type traceEvent struct {
+	Event    string   `json:"event"`
+	Line     int      `json:"line"`
+	Depth    int      `json:"depth"`
+	Function string   `json:"function,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Args     []string `json:"args,omitempty"`
+	Value    string   `json:"value,omitempty"`
+}
+
+func NewTracer(out io.Writer, format TraceFormat, lines map[syntax.Stmt]int) *Tracer {
+	return &Tracer{
+		out:       out,
+		format:    format,
+		lines:     lines,
+		functions: make(map[string]bool),
+	}
+}
+
+// only trace events that happen inside one of the given functions
+func (t *Tracer) FilterFunctions(names ...string) {
+	for _, name := range names {
+		t.functions[name] = true
+	}
+}
+
+// only trace events whose line is within [from, to], a zero bound is open
+func (t *Tracer) FilterLines(from int, to int) {
+	t.fromLine = from
+	t.toLine = to
+}
+
+func (t *Tracer) statement(stmt syntax.Stmt) {
+	t.emit(traceEvent{Event: "stmt", Line: t.lines[stmt], Name: t.describe(stmt)})
+}
+
+func (t *Tracer) enter(name string, line int, args []string) {
+	t.callStack = append(t.callStack, name)
+	t.emit(traceEvent{Event: "call", Line: line, Function: name, Args: args})
+}
+
+func (t *Tracer) exit(name string, line int, value string) {
+	t.emit(traceEvent{Event: "return", Line: line, Function: name, Value: value})
+	t.callStack = t.callStack[:len(t.callStack)-1]
+}
+
+func (t *Tracer) assign(name string, line int, value string) {
+	t.emit(traceEvent{Event: "assign", Line: line, Name: name, Value: value})
+}
+
+func (t *Tracer) emit(event traceEvent) {
+	if !t.matches(event.Line) {
+		return
+	}
+	event.Depth = len(t.callStack)
+	if event.Function == "" && len(t.callStack) > 0 {
+		event.Function = t.callStack[len(t.callStack)-1]
+	}
+
+	if t.format == TRACE_JSON {
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(t.out, "%s\n", encoded)
+		return
+	}
+
+	indent := strings.Repeat("  ", event.Depth)
+	switch event.Event {
+	case "stmt":
+		fmt.Fprintf(t.out, "[line %d] %s%s\n", event.Line, indent, event.Name)
+	case "call":
+		fmt.Fprintf(t.out, "[line %d] %s-> %s(%s)\n", event.Line, indent, event.Function, strings.Join(event.Args, ", "))
+	case "return":
+		fmt.Fprintf(t.out, "[line %d] %s<- %s = %s\n", event.Line, indent, event.Function, event.Value)
+	case "assign":
+		fmt.Fprintf(t.out, "[line %d] %s%s = %s\n", event.Line, indent, event.Name, event.Value)
+	}
+}
+
+// checks the event against the function and line filters
+func (t *Tracer) matches(line int) bool {
+	if t.fromLine > 0 && line < t.fromLine {
+		return false
+	}
+	if t.toLine > 0 && line > t.toLine {
+		return false
+	}
+	if len(t.functions) == 0 {
+		return true
+	}
+	// an event matches if any function on the call stack is being traced
+	for _, name := range t.callStack {
+		if t.functions[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// short human-readable description of a statement
+func (t *Tracer) describe(stmt syntax.Stmt) string {
+	switch s := stmt.(type) {
+	case *syntax.StatementExpression:
+		return "expression"
+	case *syntax.Print:
+		return "print"
+	case *syntax.Var:
+		if s.Constant {
+			return "const " + s.Name.Lexeme
+		}
+		return "var " + s.Name.Lexeme
+	case *syntax.DestructuringVar:
+		if s.Constant {
+			return "const [...]"
+		}
+		return "var [...]"
+	case *syntax.Enum:
+		return "enum " + s.Name.Lexeme
+	case *syntax.Block:
+		return "block"
+	case *syntax.If:
+		return "if"
+	case *syntax.While:
+		return "while"
+	case *syntax.ForIn:
+		return "for " + s.Value.Lexeme + " in"
+	case *syntax.Function:
+		return "fun " + s.Name.Lexeme
+	case *syntax.Return:
+		return "return"
+	case *syntax.Yield:
+		return "yield"
+	case *syntax.Match:
+		return "match"
+	}
+	return fmt.Sprintf("%T", stmt)
+}
@@ -0,0 +1,142 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const traceSource = `fun add(a, b) {
+  var sum = a + b;
+  return sum;
+}
+var total = add(1, 2);
+print total;
+`
+
+// runs the source with a tracer configured by setup, returns the trace
+func trace(t *testing.T, source string, format TraceFormat, setup func(*Tracer)) string {
+	t.Helper()
+	statements, lines := parse(t, source)
+
+	var out bytes.Buffer
+	tracer := NewTracer(&out, format, lines)
+	if setup != nil {
+		setup(tracer)
+	}
+	i := NewInterpreter()
+	i.SetTracer(tracer)
+	if err := interpret(t, i, statements); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestTraceText(t *testing.T) {
+	expected := `[line 1] fun add
+[line 5] var total
+[line 5]   -> add(1, 2)
+[line 2]   var sum
+[line 2]   sum = 3
+[line 3]   return
+[line 5]   <- add = 3
+[line 5] total = 3
+[line 6] print
+`
+	if got := trace(t, traceSource, TRACE_TEXT, nil); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	got := trace(t, traceSource, TRACE_JSON, nil)
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("expected 9 events, got %d:\n%s", len(lines), got)
+	}
+
+	var call traceEvent
+	if err := json.Unmarshal([]byte(lines[2]), &call); err != nil {
+		t.Fatal(err)
+	}
+	expected := traceEvent{Event: "call", Line: 5, Depth: 1, Function: "add", Args: []string{"1", "2"}}
+	if call.Event != expected.Event || call.Line != expected.Line || call.Depth != expected.Depth ||
+		call.Function != expected.Function || strings.Join(call.Args, ",") != "1,2" {
+		t.Errorf("expected %+v, got %+v", expected, call)
+	}
+
+	var assign traceEvent
+	if err := json.Unmarshal([]byte(lines[4]), &assign); err != nil {
+		t.Fatal(err)
+	}
+	if assign.Event != "assign" || assign.Name != "sum" || assign.Value != "3" || assign.Function != "add" {
+		t.Errorf("unexpected assign event %+v", assign)
+	}
+}
+
+func TestTraceFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*Tracer)
+		expected string
+	}{
+		{
+			name:  "function",
+			setup: func(tr *Tracer) { tr.FilterFunctions("add") },
+			expected: `[line 5]   -> add(1, 2)
+[line 2]   var sum
+[line 2]   sum = 3
+[line 3]   return
+[line 5]   <- add = 3
+`,
+		},
+		{
+			name:     "unknown function",
+			setup:    func(tr *Tracer) { tr.FilterFunctions("missing") },
+			expected: "",
+		},
+		{
+			name:  "lines",
+			setup: func(tr *Tracer) { tr.FilterLines(2, 3) },
+			expected: `[line 2]   var sum
+[line 2]   sum = 3
+[line 3]   return
+`,
+		},
+		{
+			name:  "open lower bound",
+			setup: func(tr *Tracer) { tr.FilterLines(0, 1) },
+			expected: `[line 1] fun add
+`,
+		},
+		{
+			name:  "open upper bound",
+			setup: func(tr *Tracer) { tr.FilterLines(6, 0) },
+			expected: `[line 6] print
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := trace(t, traceSource, TRACE_TEXT, test.setup); got != test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestTraceForLoopLines(t *testing.T) {
+	source := `for (
+  var i = 0;
+  i < 1;
+  i = i + 1
+) print i;
+`
+	got := trace(t, source, TRACE_TEXT, nil)
+	// the initializer keeps its own line rather than the one of "for"
+	if !strings.Contains(got, "[line 2] var i\n") {
+		t.Errorf("expected the initializer on line 2, got:\n%s", got)
+	}
+}
@@ -2,14 +2,81 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/hamdan-khan/interpreter/interpreter"
 	"github.com/hamdan-khan/interpreter/parser"
+	"github.com/hamdan-khan/interpreter/syntax"
 	"github.com/hamdan-khan/interpreter/token"
 )
 
-func RunFile(path string) {
+type traceOptions struct {
+	enabled   bool
+	format    string
+	functions string
+	lines     string
+	out       string
+}
+
+// builds a tracer from the command line options, nil if tracing is disabled.
+// the returned function closes the trace file, it must be called once the
+// program has finished
+func (o traceOptions) tracer(lines map[syntax.Stmt]int) (*interpreter.Tracer, func() error, error) {
+	noop := func() error { return nil }
+	if !o.enabled {
+		return nil, noop, nil
+	}
+
+	format := interpreter.TRACE_TEXT
+	switch o.format {
+	case "text":
+	case "json":
+		format = interpreter.TRACE_JSON
+	default:
+		return nil, noop, fmt.Errorf("unknown trace format %q", o.format)
+	}
+
+	var out io.Writer = os.Stderr
+	closeTrace := noop
+	if o.out != "" {
+		file, err := os.Create(o.out)
+		if err != nil {
+			return nil, noop, err
+		}
+		out = file
+		closeTrace = file.Close
+	}
+
+	tracer := interpreter.NewTracer(out, format, lines)
+	if o.functions != "" {
+		tracer.FilterFunctions(strings.Split(o.functions, ",")...)
+	}
+	if o.lines != "" {
+		// "from-to", either bound can be omitted e.g. "10-" or "-20"
+		fromText, toText, _ := strings.Cut(o.lines, "-")
+		from, to := 0, 0
+		var err error
+		if fromText != "" {
+			if from, err = strconv.Atoi(fromText); err != nil {
+				closeTrace()
+				return nil, noop, fmt.Errorf("invalid trace line range %q", o.lines)
+			}
+		}
+		if toText != "" {
+			if to, err = strconv.Atoi(toText); err != nil {
+				closeTrace()
+				return nil, noop, fmt.Errorf("invalid trace line range %q", o.lines)
+			}
+		}
+		tracer.FilterLines(from, to)
+	}
+	return tracer, closeTrace, nil
+}
+
+func RunFile(path string, trace traceOptions, warnings bool) {
 	file, err := os.ReadFile(path)
 	if err != nil {
 		fmt.Printf("Error reading file: %v", err.Error())
@@ -19,6 +86,11 @@ func RunFile(path string) {
 	scanner := token.NewScanner(fileContet)
 	scanner.Scan()
 
+	if scanner.HadError {
+		os.Exit(65)
+		return
+	}
+
 	tokens := scanner.Tokens
 	parser := parser.NewParser(tokens)
 	statements, parseErr := parser.Parse()
@@ -30,15 +102,30 @@ func RunFile(path string) {
 
 	i := interpreter.NewInterpreter()
 
+	tracer, closeTrace, tErr := trace.tracer(parser.Lines)
+	if tErr != nil {
+		fmt.Printf("Error configuring trace: %v\n", tErr)
+		os.Exit(64)
+		return
+	}
+	i.SetTracer(tracer)
+	// also closed explicitly before exiting, os.Exit skips deferred calls
+	defer closeTrace()
+
 	resolver := interpreter.NewResolver(i)
+	if warnings {
+		resolver.EnableWarnings()
+	}
 	rErr := resolver.ResolveStmts(statements)
 	if rErr != nil {
 		fmt.Printf("Error resolving: %v\n", rErr)
+		closeTrace()
 		os.Exit(65)
 		return
 	}
 
 	iError := i.Interpret(statements)
+	i.Close()
 	if iError != nil {
 		fmt.Printf("Error evaluating: %v\n", iError)
 	}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hamdan-khan/interpreter/syntax"
+)
+
+func TestTraceOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	options := traceOptions{enabled: true, format: "json", lines: "2-", out: path}
+	tracer, closeTrace, err := options.tracer(map[syntax.Stmt]int{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracer == nil {
+		t.Fatal("expected a tracer")
+	}
+	if err := closeTrace(); err != nil {
+		t.Fatalf("closing the trace file: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("trace file wasn't created: %v", err)
+	}
+
+	invalid := []struct {
+		options traceOptions
+		message string
+	}{
+		{traceOptions{enabled: true, format: "xml"}, "unknown trace format"},
+		{traceOptions{enabled: true, format: "text", lines: "a-b"}, "invalid trace line range"},
+	}
+	for _, test := range invalid {
+		if _, _, err := test.options.tracer(nil); err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("expected %q error, got %v", test.message, err)
+		}
+	}
+
+	tracer, closeTrace, err = traceOptions{}.tracer(nil)
+	if tracer != nil || err != nil || closeTrace() != nil {
+		t.Errorf("expected no tracer when tracing is disabled")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hamdan-khan/interpreter/errorHandler"
+	"github.com/hamdan-khan/interpreter/interpreter"
+	"github.com/hamdan-khan/interpreter/parser"
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// golden-file conformance tests in the style of the crafting interpreters suite.
+// every ".lox" file under testdata is run and its output is compared against
+// expectations embedded as comments:
+//
+//	print 1; // expect: 1
+//	-"a";    // expect runtime error: Operator must be a number
+//	var = 1; // Error at '=': Expected variable name
+//	// [line 3] Error at end: Expected ';' after expression!
+var (
+	expectOutput       = regexp.MustCompile(`// expect: ?(.*)`)
+	expectRuntimeError = regexp.MustCompile(`// expect runtime error: (.+)`)
+	expectError        = regexp.MustCompile(`// (Error.*)`)
+	expectErrorAtLine  = regexp.MustCompile(`// \[line (\d+)\] (Error.*)`)
+)
+
+// output of a program, or what its comments expect it to be
+type result struct {
+	output       []string
+	errors       []string
+	runtimeError string
+	runtimeLine  int
+}
+
+func TestConformance(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*", "*.lox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test files found under testdata")
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(file), "testdata/"), ".lox")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := parseExpectations(string(source))
+			actual := runSource(t, string(source))
+
+			if !equalLines(expected.output, actual.output) {
+				t.Errorf("output mismatch\nexpected:\n%s\ngot:\n%s", strings.Join(expected.output, "\n"), strings.Join(actual.output, "\n"))
+			}
+			if !equalLines(expected.errors, actual.errors) {
+				t.Errorf("error mismatch\nexpected:\n%s\ngot:\n%s", strings.Join(expected.errors, "\n"), strings.Join(actual.errors, "\n"))
+			}
+			if expected.runtimeError != actual.runtimeError || expected.runtimeLine != actual.runtimeLine {
+				t.Errorf("runtime error mismatch\nexpected: %q at line %d\ngot: %q at line %d",
+					expected.runtimeError, expected.runtimeLine, actual.runtimeError, actual.runtimeLine)
+			}
+		})
+	}
+}
+
+func parseExpectations(source string) result {
+	expected := result{}
+	for idx, line := range strings.Split(source, "\n") {
+		lineNumber := idx + 1
+		if match := expectOutput.FindStringSubmatch(line); match != nil {
+			expected.output = append(expected.output, match[1])
+		} else if match := expectRuntimeError.FindStringSubmatch(line); match != nil {
+			expected.runtimeError = match[1]
+			expected.runtimeLine = lineNumber
+		} else if match := expectErrorAtLine.FindStringSubmatch(line); match != nil {
+			expected.errors = append(expected.errors, fmt.Sprintf("[line %s] %s", match[1], match[2]))
+		} else if match := expectError.FindStringSubmatch(line); match != nil {
+			expected.errors = append(expected.errors, fmt.Sprintf("[line %d] %s", lineNumber, match[1]))
+		}
+	}
+	return expected
+}
+
+// runs the whole pipeline the same way RunFile does, but captures the output
+func runSource(t *testing.T, source string) (res result) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	errorHandler.Output = &stderr
+	defer func() {
+		errorHandler.Output = os.Stdout
+		if r := recover(); r != nil {
+			t.Fatalf("interpreter panicked: %v", r)
+		}
+		res.output = splitLines(stdout.String())
+		res.errors = splitLines(stderr.String())
+	}()
+
+	scanner := token.NewScanner(source)
+	scanner.Scan()
+	if scanner.HadError {
+		return res
+	}
+
+	p := parser.NewParser(scanner.Tokens)
+	statements, err := p.Parse()
+	if err != nil {
+		return res
+	}
+
+	i := interpreter.NewInterpreter()
+	i.SetOutput(&stdout)
+	if err := interpreter.NewResolver(i).ResolveStmts(statements); err != nil {
+		return res
+	}
+	defer i.Close()
+
+	if err := i.Interpret(statements); err != nil {
+		var runtimeErr *interpreter.RuntimeError
+		if !errors.As(err, &runtimeErr) {
+			t.Fatalf("unexpected error type %T: %v", err, err)
+		}
+		res.runtimeError = runtimeErr.Message
+		res.runtimeLine = runtimeErr.Token.LineNumber
+	}
+	return res
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func equalLines(expected []string, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for idx := range expected {
+		if strings.TrimSpace(expected[idx]) != strings.TrimSpace(actual[idx]) {
+			return false
+		}
+	}
+	return true
+}
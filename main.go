@@ -1,19 +1,35 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 )
 
 func main() {
-	argsLen := len(os.Args)
+	trace := traceOptions{}
+	flag.BoolVar(&trace.enabled, "trace", false, "log executed statements, calls and assignments")
+	flag.StringVar(&trace.format, "trace-format", "text", "trace output format: text or json")
+	flag.StringVar(&trace.functions, "trace-func", "", "comma separated function names to trace")
+	flag.StringVar(&trace.lines, "trace-lines", "", "line range to trace, e.g. 10-20")
+	flag.StringVar(&trace.out, "trace-out", "", "file to write the trace to (default stderr)")
+	warnings := flag.Bool("warnings", false, "report likely mistakes, like non-exhaustive match statements")
+	flag.Parse()
 
-	if argsLen > 2 {
-		fmt.Println("Invalid arguments. Usage: interpreter [file]")
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "test" {
+		if !RunTests(args[1:]) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(args) > 1 {
+		fmt.Println("Invalid arguments. Usage: interpreter [--trace] [file] | interpreter test [path...]")
 	} else {
 		fmt.Println("Interpreter starting...")
-		if argsLen == 2 {
-			RunFile(os.Args[1])
+		if len(args) == 1 {
+			RunFile(args[0], trace, *warnings)
 		} else {
 			RunPrompt()
 		}
@@ -0,0 +1,518 @@
+package parser
+
+import (
+	"github.com/hamdan-khan/interpreter/syntax"
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// expressions are parsed with a Pratt parser: every token type has a rule
+// saying how to parse it at the start of an expression (prefix), after a
+// complete left operand (infix), and how tightly it binds as an infix
+// operator. adding an operator is a matter of adding a row to the table.
+
+type precedence int
+
+// from loosest to tightest binding
+const (
+	PREC_NONE        precedence = iota
+	PREC_ASSIGNMENT             // = += -= *= /= %=
+	PREC_CONDITIONAL            // ?:
+	PREC_COALESCE               // ??
+	PREC_OR                     // or
+	PREC_AND                    // and
+	PREC_EQUALITY               // == !=
+	PREC_COMPARISON             // < > <= >=
+	PREC_RANGE                  // ..
+	PREC_BIT_OR                 // |
+	PREC_BIT_XOR                // ^
+	PREC_BIT_AND                // &
+	PREC_SHIFT                  // << >>
+	PREC_TERM                   // + -
+	PREC_FACTOR                 // * / %
+	PREC_UNARY                  // ! - ~ ++ -- (prefix)
+	PREC_POWER                  // **
+	PREC_POSTFIX                // ++ -- (postfix)
+	PREC_CALL                   // () []
+)
+
+type associativity int
+
+const (
+	LEFT_ASSOC associativity = iota
+	RIGHT_ASSOC
+)
+
+// the operator (or first) token has already been consumed when these are called
+type prefixRule func(p *Parser) (syntax.Expr, error)
+type infixRule func(p *Parser, left syntax.Expr) (syntax.Expr, error)
+
+type parseRule struct {
+	prefix        prefixRule
+	infix         infixRule
+	precedence    precedence
+	associativity associativity
+}
+
+var rules map[token.TokenType]parseRule
+
+// the table is filled in init because the rules refer back to it
+func init() {
+	rules = map[token.TokenType]parseRule{
+		token.EQUAL:         {infix: (*Parser).assignment, precedence: PREC_ASSIGNMENT, associativity: RIGHT_ASSOC},
+		token.PLUS_EQUAL:    {infix: (*Parser).compoundAssignment, precedence: PREC_ASSIGNMENT, associativity: RIGHT_ASSOC},
+		token.MINUS_EQUAL:   {infix: (*Parser).compoundAssignment, precedence: PREC_ASSIGNMENT, associativity: RIGHT_ASSOC},
+		token.STAR_EQUAL:    {infix: (*Parser).compoundAssignment, precedence: PREC_ASSIGNMENT, associativity: RIGHT_ASSOC},
+		token.SLASH_EQUAL:   {infix: (*Parser).compoundAssignment, precedence: PREC_ASSIGNMENT, associativity: RIGHT_ASSOC},
+		token.PERCENT_EQUAL: {infix: (*Parser).compoundAssignment, precedence: PREC_ASSIGNMENT, associativity: RIGHT_ASSOC},
+
+		token.QUESTION:          {infix: (*Parser).conditional, precedence: PREC_CONDITIONAL, associativity: RIGHT_ASSOC},
+		token.QUESTION_QUESTION: {infix: (*Parser).logical, precedence: PREC_COALESCE},
+		token.OR:                {infix: (*Parser).logical, precedence: PREC_OR},
+		token.AND:               {infix: (*Parser).logical, precedence: PREC_AND},
+
+		token.EQUAL_EQUAL:     {infix: (*Parser).binary, precedence: PREC_EQUALITY},
+		token.NOT_EQUAL:       {infix: (*Parser).binary, precedence: PREC_EQUALITY},
+		token.GREATER:         {infix: (*Parser).binary, precedence: PREC_COMPARISON},
+		token.GREATER_EQUAL:   {infix: (*Parser).binary, precedence: PREC_COMPARISON},
+		token.LESS:            {infix: (*Parser).binary, precedence: PREC_COMPARISON},
+		token.LESS_EQUAL:      {infix: (*Parser).binary, precedence: PREC_COMPARISON},
+		token.DOT_DOT:         {infix: (*Parser).binary, precedence: PREC_RANGE},
+		token.PIPE:            {infix: (*Parser).binary, precedence: PREC_BIT_OR},
+		token.CARET:           {infix: (*Parser).binary, precedence: PREC_BIT_XOR},
+		token.AMPERSAND:       {infix: (*Parser).binary, precedence: PREC_BIT_AND},
+		token.LESS_LESS:       {infix: (*Parser).binary, precedence: PREC_SHIFT},
+		token.GREATER_GREATER: {infix: (*Parser).binary, precedence: PREC_SHIFT},
+		token.PLUS:            {infix: (*Parser).binary, precedence: PREC_TERM},
+		token.MINUS:           {prefix: (*Parser).unary, infix: (*Parser).binary, precedence: PREC_TERM},
+		token.STAR:            {infix: (*Parser).binary, precedence: PREC_FACTOR},
+		token.SLASH:           {infix: (*Parser).binary, precedence: PREC_FACTOR},
+		token.PERCENT:         {infix: (*Parser).binary, precedence: PREC_FACTOR},
+		token.EXCLAMATION:     {prefix: (*Parser).unary},
+		token.TILDE:           {prefix: (*Parser).unary},
+		// binds tighter than a unary operator on its left, so -2 ** 2 is -(2 ** 2)
+		token.STAR_STAR: {infix: (*Parser).binary, precedence: PREC_POWER, associativity: RIGHT_ASSOC},
+
+		token.PLUS_PLUS:   {prefix: (*Parser).prefixIncrement, infix: (*Parser).postfixIncrement, precedence: PREC_POSTFIX},
+		token.MINUS_MINUS: {prefix: (*Parser).prefixIncrement, infix: (*Parser).postfixIncrement, precedence: PREC_POSTFIX},
+
+		token.LEFT_PAREN:   {prefix: (*Parser).grouping, infix: (*Parser).call, precedence: PREC_CALL},
+		token.LEFT_BRACKET: {prefix: (*Parser).list, infix: (*Parser).index, precedence: PREC_CALL},
+		token.QUESTION_DOT: {infix: (*Parser).optional, precedence: PREC_CALL},
+		token.DOT:          {infix: (*Parser).get, precedence: PREC_CALL},
+
+		token.NUMBER:        {prefix: (*Parser).literal},
+		token.STRING:        {prefix: (*Parser).literal},
+		token.TRUE:          {prefix: (*Parser).literal},
+		token.FALSE:         {prefix: (*Parser).literal},
+		token.NIL:           {prefix: (*Parser).literal},
+		token.INTERPOLATION: {prefix: (*Parser).interpolation},
+		token.IDENTIFIER:    {prefix: (*Parser).variable},
+	}
+}
+
+// expression -> assignment ;
+func (p *Parser) expression() (syntax.Expr, error) {
+	return p.parsePrecedence(PREC_ASSIGNMENT)
+}
+
+// parses an expression whose infix operators all bind at least as
+// tightly as the given precedence
+func (p *Parser) parsePrecedence(prec precedence) (syntax.Expr, error) {
+	prefix := rules[p.peek().TokenType].prefix
+	if prefix == nil {
+		return nil, p.error(p.peek(), "Expected expression.")
+	}
+	p.advance()
+	left, err := prefix(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// EOF and tokens without an infix rule have PREC_NONE, which ends the loop
+	for prec <= rules[p.peek().TokenType].precedence {
+		infix := rules[p.advance().TokenType].infix
+		left, err = infix(p, left)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return left, nil
+}
+
+// parses the right operand of an infix operator, a left-associative
+// operator only accepts tighter operators on its right so a - b - c is (a - b) - c
+func (p *Parser) rightOperand(operator token.Token) (syntax.Expr, error) {
+	rule := rules[operator.TokenType]
+	if rule.associativity == RIGHT_ASSOC {
+		return p.parsePrecedence(rule.precedence)
+	}
+	return p.parsePrecedence(rule.precedence + 1)
+}
+
+// how can left side (l-value) of an assignment be an expression?
+// example: someObject(x+y).someField = 10
+// does this mean any expression can be an assignment target?
+// no, only variables, list elements and destructuring targets can be
+// assignment targets which we later validate
+func (p *Parser) assignment(left syntax.Expr) (syntax.Expr, error) {
+	operator := p.previous()
+	right, err := p.rightOperand(operator)
+	if err != nil {
+		return nil, err
+	}
+
+	switch left := left.(type) {
+	case *syntax.Variable:
+		return &syntax.Assign{
+			Name:  left.Name,
+			Value: right,
+		}, nil
+	case *syntax.Index:
+		if target, ok := indexTarget(left, operator); ok {
+			target.Value = right
+			return target, nil
+		}
+	case *syntax.List:
+		// [a, b] = [b, a], the list literal on the left is reused as the target
+		if validTarget(left) {
+			return &syntax.DestructuringAssign{Target: left, Value: right}, nil
+		}
+	}
+	return nil, p.error(operator, "Invalid assignment target.")
+}
+
+// a destructuring target can only contain variables, nested targets and
+// a final spread of a variable that receives the remaining values
+func validTarget(target *syntax.List) bool {
+	for idx, element := range target.Elements {
+		switch element := element.(type) {
+		case *syntax.Variable:
+		case *syntax.List:
+			if !validTarget(element) {
+				return false
+			}
+		case *syntax.Spread:
+			if _, ok := element.Expression.(*syntax.Variable); !ok || idx != len(target.Elements)-1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Parser) compoundAssignment(left syntax.Expr) (syntax.Expr, error) {
+	operator := p.previous()
+	right, err := p.rightOperand(operator)
+	if err != nil {
+		return nil, err
+	}
+
+	switch left := left.(type) {
+	case *syntax.Variable:
+		return &syntax.CompoundAssign{
+			Name:     left.Name,
+			Operator: operator,
+			Value:    right,
+		}, nil
+	case *syntax.Index:
+		if target, ok := indexTarget(left, operator); ok {
+			target.Value = right
+			return target, nil
+		}
+	}
+	return nil, p.error(operator, "Invalid assignment target.")
+}
+
+// an element of an optional chain can't be assigned, there may be nothing
+// to assign to
+func indexTarget(index *syntax.Index, operator token.Token) (*syntax.IndexSet, bool) {
+	if index.Optional {
+		return nil, false
+	}
+	return &syntax.IndexSet{
+		Object:   index.Object,
+		Bracket:  index.Bracket,
+		Index:    index.Index,
+		Operator: operator,
+	}, true
+}
+
+// conditional -> expression "?" expression ":" expression
+func (p *Parser) conditional(condition syntax.Expr) (syntax.Expr, error) {
+	operator := p.previous()
+	// the middle operand is delimited by "?" and ":" so it can be any expression
+	thenBranch, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(token.COLON, "Expected ':' after then branch of conditional expression"); err != nil {
+		return nil, err
+	}
+	// right-associative, a ? b : c ? d : e is a ? b : (c ? d : e)
+	elseBranch, err := p.rightOperand(operator)
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Conditional{Condition: condition, ThenBranch: thenBranch, ElseBranch: elseBranch}, nil
+}
+
+// "and", "or" and "??"
+func (p *Parser) logical(left syntax.Expr) (syntax.Expr, error) {
+	operator := p.previous()
+	right, err := p.rightOperand(operator)
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Logical{
+		Left:     left,
+		Operator: operator,
+		Right:    right,
+	}, nil
+}
+
+func (p *Parser) binary(left syntax.Expr) (syntax.Expr, error) {
+	operator := p.previous()
+	right, err := p.rightOperand(operator)
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Binary{
+		Left:     left,
+		Operator: operator,
+		Right:    right,
+	}, nil
+}
+
+// unary -> ( "!" | "-" | "~" ) unary
+func (p *Parser) unary() (syntax.Expr, error) {
+	operator := p.previous()
+	right, err := p.parsePrecedence(PREC_UNARY)
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Unary{
+		Right:    right,
+		Operator: operator,
+	}, nil
+}
+
+// ( "++" | "--" ) unary
+func (p *Parser) prefixIncrement() (syntax.Expr, error) {
+	operator := p.previous()
+	target, err := p.parsePrecedence(PREC_UNARY)
+	if err != nil {
+		return nil, err
+	}
+	return p.increment(operator, target, true)
+}
+
+// call ( "++" | "--" )
+func (p *Parser) postfixIncrement(target syntax.Expr) (syntax.Expr, error) {
+	return p.increment(p.previous(), target, false)
+}
+
+// like assignment, only variables and list elements can be incremented
+func (p *Parser) increment(operator token.Token, target syntax.Expr, prefix bool) (syntax.Expr, error) {
+	switch target := target.(type) {
+	case *syntax.Variable:
+		return &syntax.Increment{Name: target.Name, Operator: operator, Prefix: prefix}, nil
+	case *syntax.Index:
+		if indexSet, ok := indexTarget(target, operator); ok {
+			indexSet.Prefix = prefix
+			return indexSet, nil
+		}
+	}
+	return nil, p.error(operator, "Invalid increment target.")
+}
+
+// call -> callee "(" arguments? ")"
+// arguments -> argument ( "," argument )*
+// argument -> ( IDENTIFIER ":" | "..." )? expression
+func (p *Parser) call(callee syntax.Expr) (syntax.Expr, error) {
+	args := []syntax.Expr{}
+	named := []syntax.NamedArgument{}
+
+	if !p.check(token.RIGHT_PAREN) {
+		for {
+			if len(args)+len(named) >= 255 {
+				return nil, p.error(p.peek(), "Too many arguments. (limit = 255)")
+			}
+			if p.check(token.IDENTIFIER) && p.checkNext(token.COLON) {
+				name := p.advance()
+				p.advance()
+				value, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+				named = append(named, syntax.NamedArgument{Name: name, Value: value})
+			} else {
+				if len(named) > 0 {
+					return nil, p.error(p.peek(), "Positional argument can't follow a named argument.")
+				}
+				// a spread argument passes every value of an iterable positionally
+				spread := p.match(token.ELLIPSIS)
+				ellipsis := p.previous()
+				expr, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+				if spread {
+					expr = &syntax.Spread{Ellipsis: ellipsis, Expression: expr}
+				}
+				args = append(args, expr)
+			}
+
+			// if comma is not found after argument, it means the args list is consumed
+			if !p.match(token.COMMA) {
+				break
+			}
+		}
+	}
+
+	paren, err := p.consume(token.RIGHT_PAREN, "Expected ')' after arguments")
+	if err != nil {
+		return nil, err
+	}
+
+	return &syntax.Call{
+		Callee:         callee,
+		Arguments:      args,
+		NamedArguments: named,
+		Paren:          paren,
+	}, nil
+}
+
+// index -> object "[" expression "]"
+func (p *Parser) index(object syntax.Expr) (syntax.Expr, error) {
+	index, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	bracket, err := p.consume(token.RIGHT_BRACKET, "Expected ']' after index")
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Index{Object: object, Bracket: bracket, Index: index}, nil
+}
+
+// optional -> expression "?." ( "(" arguments? ")" | "[" expression "]" | IDENTIFIER )
+func (p *Parser) optional(left syntax.Expr) (syntax.Expr, error) {
+	switch {
+	case p.check(token.IDENTIFIER):
+		expr, err := p.get(left)
+		if err != nil {
+			return nil, err
+		}
+		expr.(*syntax.Get).Optional = true
+		return expr, nil
+	case p.match(token.LEFT_PAREN):
+		expr, err := p.call(left)
+		if err != nil {
+			return nil, err
+		}
+		expr.(*syntax.Call).Optional = true
+		return expr, nil
+	case p.match(token.LEFT_BRACKET):
+		expr, err := p.index(left)
+		if err != nil {
+			return nil, err
+		}
+		expr.(*syntax.Index).Optional = true
+		return expr, nil
+	}
+	return nil, p.error(p.peek(), "Expected property name, '(' or '[' after '?.'")
+}
+
+// get -> expression "." IDENTIFIER
+func (p *Parser) get(object syntax.Expr) (syntax.Expr, error) {
+	name, err := p.consume(token.IDENTIFIER, "Expected property name after '.'")
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Get{Object: object, Name: name}, nil
+}
+
+// list -> "[" ( element ( "," element )* ","? )? "]"
+// element -> "..."? expression
+func (p *Parser) list() (syntax.Expr, error) {
+	bracket := p.previous()
+	elements := []syntax.Expr{}
+	for !p.check(token.RIGHT_BRACKET) {
+		if p.match(token.ELLIPSIS) {
+			ellipsis := p.previous()
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, &syntax.Spread{Ellipsis: ellipsis, Expression: expr})
+		} else {
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, expr)
+		}
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	if _, err := p.consume(token.RIGHT_BRACKET, "Expected ']' after list elements"); err != nil {
+		return nil, err
+	}
+	return &syntax.List{Bracket: bracket, Elements: elements}, nil
+}
+
+// grouping -> "(" expression ")"
+func (p *Parser) grouping() (syntax.Expr, error) {
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(token.RIGHT_PAREN, "Expected ')' after expression.")
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Grouping{Expression: expr}, nil
+}
+
+// literal -> NUMBER | STRING | "true" | "false" | "nil"
+func (p *Parser) literal() (syntax.Expr, error) {
+	switch p.previous().TokenType {
+	case token.FALSE:
+		return &syntax.Literal{Value: false}, nil
+	case token.TRUE:
+		return &syntax.Literal{Value: true}, nil
+	case token.NIL:
+		return &syntax.Literal{Value: nil}, nil
+	}
+	return &syntax.Literal{Value: p.previous().Literal}, nil
+}
+
+func (p *Parser) variable() (syntax.Expr, error) {
+	return &syntax.Variable{Name: p.previous()}, nil
+}
+
+// interpolation -> ( INTERPOLATION expression )+ STRING
+func (p *Parser) interpolation() (syntax.Expr, error) {
+	parts := []syntax.Expr{}
+	for {
+		parts = append(parts, &syntax.Literal{Value: p.previous().Literal})
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+
+		if p.match(token.INTERPOLATION) {
+			continue
+		}
+		if _, err := p.consume(token.STRING, "Expected end of string interpolation"); err != nil {
+			return nil, err
+		}
+		parts = append(parts, &syntax.Literal{Value: p.previous().Literal})
+		return &syntax.Interpolation{Parts: parts}, nil
+	}
+}
@@ -9,11 +9,16 @@ import (
 type Parser struct {
 	tokens  []token.Token
 	current int
+	// source line of every parsed statement, used for runtime tracing
+	Lines map[syntax.Stmt]int
+	// whether the function body being parsed contains a yield
+	yields bool
 }
 
 func NewParser(tokens []token.Token) Parser {
 	return Parser{
 		tokens: tokens,
+		Lines:  make(map[syntax.Stmt]int),
 	}
 }
 
@@ -59,6 +64,14 @@ func (p *Parser) check(tok token.TokenType) bool {
 	return p.peek().TokenType == tok
 }
 
+// checks the token after the next one, no side-effect
+func (p *Parser) checkNext(tok token.TokenType) bool {
+	if p.isAtEnd() || p.tokens[p.current+1].TokenType == token.EOF {
+		return false
+	}
+	return p.tokens[p.current+1].TokenType == tok
+}
+
 // compares the given token types with the current token
 // and advances if a match is found
 func (p *Parser) match(toks ...token.TokenType) bool {
@@ -71,235 +84,6 @@ func (p *Parser) match(toks ...token.TokenType) bool {
 	return false
 }
 
-// assignment -> IDENTIFIER "=" assignment | logic_or ;
-func (p *Parser) assignment() (syntax.Expr, error) {
-	// how can left side (l-value) of an assignment be an expression?
-	// example: someObject(x+y).someField = 10
-	// does this mean any expression can be an assignment target?
-	// no, only variables can be assignment targets which we later validate
-	expr, err := p.equality()
-	if err != nil {
-		return nil, err
-	}
-
-	if p.match(token.EQUAL) {
-		operator := p.previous()
-		right, err := p.assignment()
-
-		if err != nil {
-			return nil, err
-		}
-
-		if expr, ok := expr.(*syntax.Variable); ok {
-			return &syntax.Assign{
-				Name:  expr.Name,
-				Value: right,
-			}, nil
-		}
-		return nil, p.error(operator, "Invalid assignment target.")
-	}
-
-	return expr, nil
-}
-
-// expression -> assignment ;
-func (p *Parser) expression() (syntax.Expr, error) {
-	return p.assignment()
-}
-
-// equality -> comparison ( ( "!=" | "==" ) comparison )*
-func (p *Parser) equality() (syntax.Expr, error) {
-	expr, err := p.comparison()
-	if err != nil {
-		return nil, err
-	}
-
-	for p.match(token.EQUAL_EQUAL, token.NOT_EQUAL) {
-		operator := p.previous()
-		right, err := p.comparison()
-		if err != nil {
-			return nil, err
-		}
-		expr = &syntax.Binary{
-			Left:     expr,
-			Operator: operator,
-			Right:    right,
-		}
-	}
-
-	return expr, nil
-}
-
-// comparison -> term ( ( ">" | ">=" | "<" | "<=" ) term )*
-func (p *Parser) comparison() (syntax.Expr, error) {
-	expr, err := p.term()
-	if err != nil {
-		return nil, err
-	}
-
-	for p.match(token.GREATER, token.GREATER_EQUAL, token.LESS, token.LESS_EQUAL) {
-		operator := p.previous()
-		right, err := p.term()
-		if err != nil {
-			return nil, err
-		}
-		expr = &syntax.Binary{
-			Left:     expr,
-			Operator: operator,
-			Right:    right,
-		}
-	}
-
-	return expr, nil
-}
-
-func (p *Parser) term() (syntax.Expr, error) {
-	expr, err := p.factor()
-	if err != nil {
-		return nil, err
-	}
-
-	for p.match(token.PLUS, token.MINUS) {
-		operator := p.previous()
-		right, err := p.factor()
-		if err != nil {
-			return nil, err
-		}
-		expr = &syntax.Binary{
-			Left:     expr,
-			Operator: operator,
-			Right:    right,
-		}
-	}
-
-	return expr, nil
-}
-
-func (p *Parser) factor() (syntax.Expr, error) {
-	expr, err := p.unary()
-	if err != nil {
-		return nil, err
-	}
-
-	for p.match(token.SLASH, token.STAR) {
-		operator := p.previous()
-		right, err := p.unary()
-		if err != nil {
-			return nil, err
-		}
-		expr = &syntax.Binary{
-			Left:     expr,
-			Operator: operator,
-			Right:    right,
-		}
-	}
-
-	return expr, nil
-}
-
-// unary -> ( "!" | "-" ) unary | call
-func (p *Parser) unary() (syntax.Expr, error) {
-	if p.match(token.EXCLAMATION, token.MINUS) {
-		operator := p.previous()
-		right, err := p.unary()
-		if err != nil {
-			return nil, err
-		}
-		return &syntax.Unary{
-			Right:    right,
-			Operator: operator,
-		}, nil
-	}
-
-	return p.call()
-}
-
-// call -> primary ( "(" arguments? ")" )*
-func (p *Parser) call() (syntax.Expr, error) {
-	expr, err := p.primary()
-	if err != nil {
-		return nil, err
-	}
-
-	for {
-		if p.match(token.LEFT_PAREN) {
-			expr, err = p.finishCall(expr)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			break
-		}
-	}
-
-	return expr, nil
-}
-
-// arguments -> expression ( "," expression )*
-func (p *Parser) finishCall(callee syntax.Expr) (syntax.Expr, error) {
-	args := []syntax.Expr{}
-
-	if !p.check(token.RIGHT_PAREN) {
-		for {
-			if len(args) >= 255 {
-				return nil, p.error(p.peek(), "Too many arguments. (limit = 255)")
-			}
-			expr, err := p.expression()
-			if err != nil {
-				return nil, err
-			}
-			args = append(args, expr)
-
-			// if comma is not found after argument, it means the args list is consumed
-			if !p.match(token.COMMA) {
-				break
-			}
-		}
-	}
-
-	paren, err := p.consume(token.RIGHT_PAREN, "Expected ')' after arguments")
-	if err != nil {
-		return nil, err
-	}
-
-	return &syntax.Call{
-		Callee:    callee,
-		Arguments: args,
-		Paren:     paren,
-	}, nil
-}
-
-// primary -> NUMBER | STRING | "true" | "false" | "nil" | "(" expression ")" | IDENTIFIER
-func (p *Parser) primary() (syntax.Expr, error) {
-	if p.match(token.FALSE) {
-		return &syntax.Literal{Value: false}, nil
-	}
-	if p.match(token.TRUE) {
-		return &syntax.Literal{Value: true}, nil
-	}
-	if p.match(token.NIL) {
-		return &syntax.Literal{Value: nil}, nil
-	}
-	if p.match(token.STRING, token.NUMBER) {
-		return &syntax.Literal{Value: p.previous().Literal}, nil
-	}
-	if p.match(token.LEFT_PAREN) {
-		expr, err := p.expression()
-		if err != nil {
-			return nil, err
-		}
-		_, err = p.consume(token.RIGHT_PAREN, "Expected ')' after expression.")
-		if err != nil {
-			return nil, err
-		}
-		return &syntax.Grouping{Expression: expr}, nil
-	}
-	if p.match(token.IDENTIFIER) {
-		return &syntax.Variable{Name: p.previous()}, nil
-	}
-	return nil, p.error(p.peek(), "Expected expression.")
-}
-
 func (p *Parser) error(tok token.Token, message string) error {
 	if tok.TokenType == token.EOF {
 		return errorHandler.ReportError(tok.LineNumber, "at end", message)
@@ -326,13 +110,7 @@ func (p *Parser) synchronize() {
 		}
 
 		switch p.peek().TokenType {
-		case token.FUNCTION:
-		case token.VAR:
-		case token.FOR:
-		case token.IF:
-		case token.WHILE:
-		case token.PRINT:
-		case token.RETURN:
+		case token.FUNCTION, token.VAR, token.CONST, token.LET, token.ENUM, token.FOR, token.IF, token.WHILE, token.PRINT, token.RETURN, token.YIELD, token.MATCH:
 			return
 		}
 
@@ -342,14 +120,16 @@ func (p *Parser) synchronize() {
 
 // statements stuff
 
-// declaration -> funcDecl | varDecl | statement
+// declaration -> funcDecl | varDecl | constDecl | enumDecl | statement
 func (p *Parser) declaration() (syntax.Stmt, error) {
+	line := p.peek().LineNumber
 	if p.match(token.FUNCTION) {
 		f, err := p.function("function")
 		if err != nil {
 			p.synchronize()
 			return nil, err
 		}
+		p.Lines[f] = line
 		return f, nil
 	}
 	if p.match(token.VAR) {
@@ -358,8 +138,27 @@ func (p *Parser) declaration() (syntax.Stmt, error) {
 			p.synchronize()
 			return nil, err
 		}
+		p.Lines[v] = line
 		return v, nil
 	}
+	if p.match(token.CONST, token.LET) {
+		c, err := p.constDeclaration()
+		if err != nil {
+			p.synchronize()
+			return nil, err
+		}
+		p.Lines[c] = line
+		return c, nil
+	}
+	if p.match(token.ENUM) {
+		e, err := p.enumDeclaration()
+		if err != nil {
+			p.synchronize()
+			return nil, err
+		}
+		p.Lines[e] = line
+		return e, nil
+	}
 
 	s, sErr := p.statement()
 	if sErr != nil {
@@ -369,41 +168,121 @@ func (p *Parser) declaration() (syntax.Stmt, error) {
 	return s, nil
 }
 
-// function -> IDENTIFIER "(" parameters? ")" block
+// enumDecl -> "enum" IDENTIFIER "{" ( IDENTIFIER ( "," IDENTIFIER )* ","? )? "}"
+func (p *Parser) enumDeclaration() (syntax.Stmt, error) {
+	name, err := p.consume(token.IDENTIFIER, "Expected enum name")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(token.LEFT_BRACE, "Expected '{' after enum name"); err != nil {
+		return nil, err
+	}
+	members := []token.Token{}
+	for !p.check(token.RIGHT_BRACE) {
+		member, err := p.consume(token.IDENTIFIER, "Expected enum member name")
+		if err != nil {
+			return nil, err
+		}
+		// the enum itself has a values() method
+		if member.Lexeme == "values" {
+			return nil, p.error(member, "An enum member can't be named 'values'.")
+		}
+		members = append(members, member)
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	if _, err := p.consume(token.RIGHT_BRACE, "Expected '}' after enum members"); err != nil {
+		return nil, err
+	}
+	return &syntax.Enum{Name: name, Members: members}, nil
+}
+
+// function -> IDENTIFIER "(" parameters ")" block
 func (p *Parser) function(kind string) (syntax.Stmt, error) {
 	name, err := p.consume(token.IDENTIFIER, "Expected "+kind+" name")
 	if err != nil {
 		return nil, err
 	}
-	p.consume(token.LEFT_PAREN, "Expected '(' after "+kind+" name")
-	parameters := []token.Token{}
-	if !p.check(token.RIGHT_PAREN) {
-		for {
-			if len(parameters) >= 255 {
-				return nil, p.error(p.peek(), "Too many parameters. (limit = 255)")
-			}
-			paramName, err := p.consume(token.IDENTIFIER, "Expected parameter name")
+	if _, err := p.consume(token.LEFT_PAREN, "Expected '(' after "+kind+" name"); err != nil {
+		return nil, err
+	}
+	parameters, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(token.RIGHT_PAREN, "Expected ')' after parameters"); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.consume(token.LEFT_BRACE, "Expected '{' before "+kind+" body"); err != nil {
+		return nil, err
+	}
+	// a yield inside a nested function doesn't make this one a generator
+	enclosing := p.yields
+	p.yields = false
+	defer func() {
+		p.yields = enclosing
+	}()
+	body, err := p.blockStatement()
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Function{Name: name, Params: parameters, Body: body, Generator: p.yields}, nil
+}
+
+// parameters -> ( param ( "," param )* ( "," "..." IDENTIFIER )? | "..." IDENTIFIER )?
+// param -> IDENTIFIER ( "=" expression )?
+func (p *Parser) parameters() ([]syntax.Parameter, error) {
+	parameters := []syntax.Parameter{}
+	if p.check(token.RIGHT_PAREN) {
+		return parameters, nil
+	}
+
+	hasDefault := false
+	for {
+		if len(parameters) >= 255 {
+			return nil, p.error(p.peek(), "Too many parameters. (limit = 255)")
+		}
+		if p.match(token.ELLIPSIS) {
+			paramName, err := p.consume(token.IDENTIFIER, "Expected parameter name after '...'")
 			if err != nil {
 				return nil, err
 			}
-			parameters = append(parameters, paramName)
-			if !p.match(token.COMMA) {
-				break
+			if !p.check(token.RIGHT_PAREN) {
+				return nil, p.error(paramName, "Variadic parameter must be the last one.")
 			}
+			return append(parameters, syntax.Parameter{Name: paramName, Variadic: true}), nil
 		}
-	}
-	p.consume(token.RIGHT_PAREN, "Expected ')' after parameters")
 
-	p.consume(token.LEFT_BRACE, "Expected '{' before "+kind+" body")
-	body, err := p.blockStatement()
-	if err != nil {
-		return nil, err
+		paramName, err := p.consume(token.IDENTIFIER, "Expected parameter name")
+		if err != nil {
+			return nil, err
+		}
+		param := syntax.Parameter{Name: paramName}
+		if p.match(token.EQUAL) {
+			param.Default, err = p.expression()
+			if err != nil {
+				return nil, err
+			}
+			hasDefault = true
+		} else if hasDefault {
+			// otherwise a positional argument couldn't reach it
+			return nil, p.error(paramName, "Parameter without a default value can't follow one with a default.")
+		}
+		parameters = append(parameters, param)
+
+		if !p.match(token.COMMA) {
+			return parameters, nil
+		}
 	}
-	return &syntax.Function{Name: name, Params: parameters, Body: body}, nil
 }
 
-// varDecl -> "var" IDENTIFIER ( "=" expression )? ";"
+// varDecl -> "var" ( IDENTIFIER ( "=" expression )? | listPattern "=" expression ) ";"
 func (p *Parser) varDeclaration() (syntax.Stmt, error) {
+	if p.match(token.LEFT_BRACKET) {
+		return p.destructuringDeclaration(false)
+	}
 	name, err := p.consume(token.IDENTIFIER, "Expected variable name")
 	if err != nil {
 		return nil, err
@@ -425,14 +304,118 @@ func (p *Parser) varDeclaration() (syntax.Stmt, error) {
 	return &syntax.Var{Name: name, Initializer: initializer}, nil
 }
 
-// statement -> exprStmt | ifStmt | printStmt | whileStmt | block | returnStmt
+// constDecl -> ( "const" | "let" ) ( IDENTIFIER | listPattern ) "=" expression ";"
+func (p *Parser) constDeclaration() (syntax.Stmt, error) {
+	keyword := p.previous()
+	if p.match(token.LEFT_BRACKET) {
+		return p.destructuringDeclaration(true)
+	}
+	name, err := p.consume(token.IDENTIFIER, "Expected constant name")
+	if err != nil {
+		return nil, err
+	}
+
+	// unlike variables, constants can't be assigned later so they need a value now
+	if _, err := p.consume(token.EQUAL, "Expected '=' after '"+keyword.Lexeme+" "+name.Lexeme+"', constants must be initialized"); err != nil {
+		return nil, err
+	}
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(token.SEMICOLON, "Expected ';' after constant declaration"); err != nil {
+		return nil, err
+	}
+	return &syntax.Var{Name: name, Initializer: initializer, Constant: true}, nil
+}
+
+// declares the names of a list pattern, its opening bracket is already consumed
+func (p *Parser) destructuringDeclaration(constant bool) (syntax.Stmt, error) {
+	pattern, err := p.listPattern(p.declarationPattern)
+	if err != nil {
+		return nil, err
+	}
+	// there is nothing to destructure without a value
+	if _, err := p.consume(token.EQUAL, "Expected '=' after destructuring pattern"); err != nil {
+		return nil, err
+	}
+	initializer, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(token.SEMICOLON, "Expected ';' after variable declaration"); err != nil {
+		return nil, err
+	}
+	return &syntax.DestructuringVar{Pattern: pattern, Initializer: initializer, Constant: constant}, nil
+}
+
+// declaration_pattern -> IDENTIFIER | "[" list_pattern
+func (p *Parser) declarationPattern() (syntax.Pattern, error) {
+	if p.match(token.LEFT_BRACKET) {
+		return p.listPattern(p.declarationPattern)
+	}
+	name, err := p.consume(token.IDENTIFIER, "Expected variable name or '[' in destructuring pattern")
+	if err != nil {
+		return nil, err
+	}
+	if name.Lexeme == "_" {
+		return &syntax.WildcardPattern{Underscore: name}, nil
+	}
+	return &syntax.BindingPattern{Name: name}, nil
+}
+
+// list_pattern -> ( element ( "," element )* ( "," "..." IDENTIFIER )? | "..." IDENTIFIER )? "]"
+// the opening bracket is already consumed, element parses the nested patterns
+func (p *Parser) listPattern(element func() (syntax.Pattern, error)) (*syntax.ListPattern, error) {
+	pattern := &syntax.ListPattern{Bracket: p.previous(), Elements: []syntax.Pattern{}}
+	for !p.check(token.RIGHT_BRACKET) {
+		if p.match(token.ELLIPSIS) {
+			rest, err := p.consume(token.IDENTIFIER, "Expected name after '...'")
+			if err != nil {
+				return nil, err
+			}
+			pattern.Rest = &rest
+			if !p.check(token.RIGHT_BRACKET) {
+				return nil, p.error(rest, "Rest element must be the last one.")
+			}
+			break
+		}
+		next, err := element()
+		if err != nil {
+			return nil, err
+		}
+		pattern.Elements = append(pattern.Elements, next)
+		if !p.match(token.COMMA) {
+			break
+		}
+	}
+	if _, err := p.consume(token.RIGHT_BRACKET, "Expected ']' after pattern elements"); err != nil {
+		return nil, err
+	}
+	return pattern, nil
+}
+
+// statement -> exprStmt | ifStmt | printStmt | whileStmt | forStmt | block | returnStmt | yieldStmt | matchStmt
 func (p *Parser) statement() (syntax.Stmt, error) {
+	line := p.peek().LineNumber
+	stmt, err := p.nextStatement()
+	if err != nil {
+		return nil, err
+	}
+	p.Lines[stmt] = line
+	return stmt, nil
+}
+
+func (p *Parser) nextStatement() (syntax.Stmt, error) {
 	if p.match(token.PRINT) {
 		return p.printStatement()
 	}
 	if p.match(token.RETURN) {
 		return p.returnStatement()
 	}
+	if p.match(token.YIELD) {
+		return p.yieldStatement()
+	}
 	if p.match(token.WHILE) {
 		return p.whileStatement()
 	}
@@ -449,6 +432,9 @@ func (p *Parser) statement() (syntax.Stmt, error) {
 	if p.match(token.IF) {
 		return p.ifStatement()
 	}
+	if p.match(token.MATCH) {
+		return p.matchStatement()
+	}
 	return p.expressionStatement()
 }
 
@@ -471,16 +457,41 @@ func (p *Parser) returnStatement() (syntax.Stmt, error) {
 	return &syntax.Return{Keyword: keyword, Value: value}, nil
 }
 
+// yieldStmt -> "yield" expression? ";"
+func (p *Parser) yieldStatement() (syntax.Stmt, error) {
+	keyword := p.previous()
+	p.yields = true
+	var value syntax.Expr = nil
+	if !p.check(token.SEMICOLON) {
+		val, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		value = val
+	}
+	if _, err := p.consume(token.SEMICOLON, "Expected ';' after yield"); err != nil {
+		return nil, err
+	}
+	return &syntax.Yield{Keyword: keyword, Value: value}, nil
+}
+
 // syntax desugaring - converting "for" loop into "while" loop
 // for (init; condition; increment) body
 func (p *Parser) forStatement() (s syntax.Stmt, e error) {
+	line := p.previous().LineNumber
 	_, err := p.consume(token.LEFT_PAREN, "Expected '(' after 'for'")
 	if err != nil {
 		return nil, err
 	}
 
+	// "x in" or "k, v in" can't start a C-style initializer
+	if p.check(token.IDENTIFIER) && (p.checkNext(token.IN) || p.checkNext(token.COMMA)) {
+		return p.forInStatement()
+	}
+
 	// initializer can be nil, expression, or a declaration
 	var initializer syntax.Stmt = nil
+	initializerLine := p.peek().LineNumber
 	if p.match(token.SEMICOLON) {
 		initializer = nil
 	} else if p.match(token.VAR) {
@@ -533,7 +544,10 @@ func (p *Parser) forStatement() (s syntax.Stmt, e error) {
 	// 	incrementExpression;
 	// }
 	if increment != nil {
-		body = &syntax.Block{Statements: []syntax.Stmt{body, &syntax.StatementExpression{Expression: increment}}}
+		incrementStmt := &syntax.StatementExpression{Expression: increment}
+		p.Lines[incrementStmt] = line
+		body = &syntax.Block{Statements: []syntax.Stmt{body, incrementStmt}}
+		p.Lines[body] = line
 	}
 
 	// if condition is absent, make it true i.e. infinite loop
@@ -541,10 +555,13 @@ func (p *Parser) forStatement() (s syntax.Stmt, e error) {
 		condition = &syntax.Literal{Value: true}
 	}
 	body = &syntax.While{Condition: condition, Body: body} // body is now a while loop
+	p.Lines[body] = line
 
 	// if initializer is present, make it a block of initializer + body (which is now a while loop)
 	if initializer != nil {
+		p.Lines[initializer] = initializerLine
 		body = &syntax.Block{Statements: []syntax.Stmt{initializer, body}}
+		p.Lines[body] = line
 	}
 
 	// the "for" loop after desugaring looks like:
@@ -558,6 +575,39 @@ func (p *Parser) forStatement() (s syntax.Stmt, e error) {
 	return body, nil
 }
 
+// forInStmt -> "for" "(" IDENTIFIER ( "," IDENTIFIER )? "in" expression ")" statement
+func (p *Parser) forInStatement() (syntax.Stmt, error) {
+	var key *token.Token
+	value := p.advance()
+	if p.match(token.COMMA) {
+		first := value
+		key = &first
+		v, err := p.consume(token.IDENTIFIER, "Expected value variable name after ','")
+		if err != nil {
+			return nil, err
+		}
+		value = v
+	}
+
+	in, err := p.consume(token.IN, "Expected 'in' after loop variable")
+	if err != nil {
+		return nil, err
+	}
+	iterable, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(token.RIGHT_PAREN, "Expected ')' after for-in iterable"); err != nil {
+		return nil, err
+	}
+
+	body, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.ForIn{Key: key, Value: value, In: in, Iterable: iterable, Body: body}, nil
+}
+
 // whileStmt -> "while" "(" expression ")" statement
 func (p *Parser) whileStatement() (s syntax.Stmt, e error) {
 	_, err := p.consume(token.LEFT_PAREN, "Expected '(' after 'while'")
@@ -666,48 +716,130 @@ func (p *Parser) ifStatement() (s syntax.Stmt, e error) {
 	return &syntax.If{Condition: condition, ThenBranch: thenBranch, ElseBranch: elseBranch}, nil
 }
 
-// logic_or -> logic_and ( "or" logic_and )*
-func (p *Parser) or() (syntax.Expr, error) {
-	expr, err := p.and()
+// matchStmt -> "match" "(" expression ")" "{" ( pattern "=>" statement ","? )* "}"
+func (p *Parser) matchStatement() (syntax.Stmt, error) {
+	keyword := p.previous()
+	if _, err := p.consume(token.LEFT_PAREN, "Expected '(' after 'match'"); err != nil {
+		return nil, err
+	}
+	subject, err := p.expression()
 	if err != nil {
 		return nil, err
 	}
+	if _, err := p.consume(token.RIGHT_PAREN, "Expected ')' after match subject"); err != nil {
+		return nil, err
+	}
+	if _, err := p.consume(token.LEFT_BRACE, "Expected '{' before match arms"); err != nil {
+		return nil, err
+	}
 
-	for p.match(token.OR) {
-		operator := p.previous()
-		right, err := p.and()
+	arms := []syntax.MatchArm{}
+	for !p.check(token.RIGHT_BRACE) && !p.isAtEnd() {
+		pattern, err := p.pattern()
 		if err != nil {
 			return nil, err
 		}
-		expr = &syntax.Logical{
-			Left:     expr,
-			Operator: operator,
-			Right:    right,
+		if _, err := p.consume(token.FAT_ARROW, "Expected '=>' after pattern"); err != nil {
+			return nil, err
 		}
+		body, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		arms = append(arms, syntax.MatchArm{Pattern: pattern, Body: body})
+		p.match(token.COMMA)
 	}
 
-	return expr, nil
+	if _, err := p.consume(token.RIGHT_BRACE, "Expected '}' after match arms"); err != nil {
+		return nil, err
+	}
+	return &syntax.Match{Keyword: keyword, Subject: subject, Arms: arms}, nil
 }
 
-// logic_and -> equality ( "and" equality )*
-func (p *Parser) and() (syntax.Expr, error) {
-	expr, err := p.equality()
+// pattern -> single_pattern ( "|" single_pattern )*
+func (p *Parser) pattern() (syntax.Pattern, error) {
+	first, err := p.singlePattern()
 	if err != nil {
 		return nil, err
 	}
+	if !p.check(token.PIPE) {
+		return first, nil
+	}
 
-	for p.match(token.AND) {
-		operator := p.previous()
-		right, err := p.equality()
+	alternatives := []syntax.Pattern{first}
+	for p.match(token.PIPE) {
+		next, err := p.singlePattern()
 		if err != nil {
 			return nil, err
 		}
-		expr = &syntax.Logical{
-			Left:     expr,
-			Operator: operator,
-			Right:    right,
+		alternatives = append(alternatives, next)
+	}
+	// which alternative matched isn't known statically, so neither are the bound names
+	for _, alternative := range alternatives {
+		if name := boundName(alternative); name != nil {
+			return nil, p.error(*name, "Cannot bind a name in an alternative pattern.")
+		}
+	}
+	return &syntax.AlternativePattern{Alternatives: alternatives}, nil
+}
+
+// first name bound by the pattern, nil if it doesn't bind any
+func boundName(pattern syntax.Pattern) *token.Token {
+	switch pattern := pattern.(type) {
+	case *syntax.BindingPattern:
+		return &pattern.Name
+	case *syntax.ListPattern:
+		for _, element := range pattern.Elements {
+			if name := boundName(element); name != nil {
+				return name
+			}
+		}
+		// "..._" discards the rest without binding it
+		if pattern.Rest != nil && pattern.Rest.Lexeme != "_" {
+			return pattern.Rest
 		}
 	}
+	return nil
+}
 
-	return expr, nil
+// single_pattern -> "_" | IDENTIFIER ( "." IDENTIFIER )* | "-"? NUMBER | STRING | "true" | "false" | "nil" | "[" list_pattern
+func (p *Parser) singlePattern() (syntax.Pattern, error) {
+	if p.match(token.LEFT_BRACKET) {
+		return p.listPattern(p.pattern)
+	}
+	if p.match(token.IDENTIFIER) {
+		name := p.previous()
+		// a property access like Color.Red matches the value it evaluates to
+		if p.check(token.DOT) {
+			var value syntax.Expr = &syntax.Variable{Name: name}
+			for p.match(token.DOT) {
+				property, err := p.get(value)
+				if err != nil {
+					return nil, err
+				}
+				value = property
+			}
+			return &syntax.LiteralPattern{Value: value}, nil
+		}
+		if name.Lexeme == "_" {
+			return &syntax.WildcardPattern{Underscore: name}, nil
+		}
+		return &syntax.BindingPattern{Name: name}, nil
+	}
+	if p.match(token.MINUS) {
+		operator := p.previous()
+		if _, err := p.consume(token.NUMBER, "Expected number after '-' in pattern"); err != nil {
+			return nil, err
+		}
+		number := &syntax.Literal{Value: p.previous().Literal}
+		return &syntax.LiteralPattern{Value: &syntax.Unary{Operator: operator, Right: number}}, nil
+	}
+	if p.match(token.NUMBER, token.STRING, token.TRUE, token.FALSE, token.NIL) {
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		return &syntax.LiteralPattern{Value: value}, nil
+	}
+	return nil, p.error(p.peek(), "Expected pattern.")
 }
@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hamdan-khan/interpreter/errorHandler"
+	"github.com/hamdan-khan/interpreter/syntax"
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+func TestExpressionPrecedence(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"1 + 2 * 3", "(+ 1 (* 2 3))"},
+		{"1 - 2 - 3", "(- (- 1 2) 3)"},
+		{"2 ** 3 ** 2", "(** 2 (** 3 2))"},
+		{"-2 ** 2", "(- (** 2 2))"},
+		{"2 ** -1", "(** 2 (- 1))"},
+		{"!a == b", "(== (! a) b)"},
+		{"a or b and c", "(or a (and b c))"},
+		{"a and b or c", "(or (and a b) c)"},
+		{"a == b and c != d", "(and (== a b) (!= c d))"},
+		{"a ?? b or c", "(?? a (or b c))"},
+		{"a ? b : c ? d : e", "(?: a b (?: c d e))"},
+		{"a = b = c", "(= a (= b c))"},
+		{"a += b ? 1 : 2", "(+= a (?: b 1 2))"},
+		{"1 | 2 ^ 3 & 4 << 5", "(| 1 (^ 2 (& 3 (<< 4 5))))"},
+		{"1 < 2 == 3 >= 4", "(== (< 1 2) (>= 3 4))"},
+		{"-a++", "(- (a ++))"},
+		{"++a ** 2", ""},
+		{"f(1)(2)[0]", "(index (call (call f 1) 2) 0)"},
+		{"a?.[0]?.(1) ?? b", "(?? (?.call (?.index a 0) 1) b)"},
+		{"-a?.[0]", "(- (?.index a 0))"},
+		{"a.b?.c(1)", "(call (?.c (.b a)) 1)"},
+		{"-f(x)", "(- (call f x))"},
+		{"a[0] = b[1] = c", "(= (index a 0) (= (index b 1) c))"},
+		{"a[i] += 1", "(+= (index a i) 1)"},
+		{"-a[0]++", "(- ((index a 0) ++))"},
+		{"--a[0][1]", "(-- (index (index a 0) 1))"},
+		{"a?.[0] = 1", ""},
+		{"(1 + 2) * 3", "(* (group (+ 1 2)) 3)"},
+	}
+
+	errorHandler.Output = io.Discard
+	defer func() { errorHandler.Output = os.Stdout }()
+	for _, test := range tests {
+		scanner := token.NewScanner(test.source)
+		scanner.Scan()
+		p := NewParser(scanner.Tokens)
+		expr, err := p.expression()
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("%s: expected a parse error", test.source)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.source, err)
+			continue
+		}
+		if !p.isAtEnd() {
+			t.Errorf("%s: unparsed tokens after %q", test.source, p.peek().Lexeme)
+			continue
+		}
+		actual, err := syntax.NewAstPrinter().Print(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.source, test.expected, actual)
+		}
+	}
+}
+
+func TestAlternativePatternBindings(t *testing.T) {
+	tests := []struct {
+		source string
+		valid  bool
+	}{
+		{"match (x) { 1 | 2 => print x; }", true},
+		{"match (x) { [1, _] | [2, _] => print x; }", true},
+		{"match (x) { [1, ..._] | [2, ..._] => print x; }", true},
+		{"match (x) { [1, ...rest] | [2] => print x; }", false},
+		{"match (x) { [1, [y]] | [2] => print x; }", false},
+		{"match (x) { 1 | y => print x; }", false},
+	}
+
+	errorHandler.Output = io.Discard
+	defer func() { errorHandler.Output = os.Stdout }()
+	for _, test := range tests {
+		scanner := token.NewScanner(test.source)
+		scanner.Scan()
+		p := NewParser(scanner.Tokens)
+		_, err := p.Parse()
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", test.source, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected a parse error", test.source)
+		}
+	}
+}
+
+func FuzzParser(f *testing.F) {
+	seeds := []string{
+		"var a = 1;",
+		"fun f(a, b) { return a + b; } print f(1, 2);",
+		"fun f",
+		"fun f(",
+		"fun f(a b) {}",
+		"fun f() 1;",
+		"for (var i = 0; i < 10; i = i + 1) print i;",
+		"for (;;",
+		"if (a) { } else",
+		"while (true) { var x = 1;",
+		"a = b = c;",
+		"(a) = 1;",
+		"a ? b : c ? d;",
+		"a ?? b or c and d;",
+		"a?.[0]?.(1)?.b;",
+		"enum Color { Red, Green, } print Color.Red.name;",
+		"enum { }",
+		"-a++ ** --b;",
+		"f(1)(2)(3);",
+		"{{{{",
+		"}",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	errorHandler.Output = io.Discard
+	f.Fuzz(func(t *testing.T, source string) {
+		scanner := token.NewScanner(source)
+		scanner.Scan()
+		p := NewParser(scanner.Tokens)
+		statements, err := p.Parse()
+		if err == nil {
+			for _, stmt := range statements {
+				if stmt == nil {
+					t.Fatalf("parser returned a nil statement without an error")
+				}
+			}
+		}
+	})
+}
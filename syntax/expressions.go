@@ -11,6 +11,16 @@ type Visitor interface {
 	VisitAssignExpr(expr *Assign) (any, error)
 	VisitLogicalExpr(expr *Logical) (any, error)
 	VisitCallExpr(expr *Call) (any, error)
+	VisitIndexExpr(expr *Index) (any, error)
+	VisitIndexSetExpr(expr *IndexSet) (any, error)
+	VisitInterpolationExpr(expr *Interpolation) (any, error)
+	VisitCompoundAssignExpr(expr *CompoundAssign) (any, error)
+	VisitIncrementExpr(expr *Increment) (any, error)
+	VisitConditionalExpr(expr *Conditional) (any, error)
+	VisitListExpr(expr *List) (any, error)
+	VisitSpreadExpr(expr *Spread) (any, error)
+	VisitDestructuringAssignExpr(expr *DestructuringAssign) (any, error)
+	VisitGetExpr(expr *Get) (any, error)
 }
 
 type Expr interface {
@@ -72,6 +82,8 @@ func (e *Assign) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitAssignExpr(e)
 }
 
+// "and", "or" and the nil-coalescing "??", the right operand is only
+// evaluated when the left one doesn't decide the result
 type Logical struct {
 	Left     Expr
 	Operator token.Token
@@ -83,11 +95,134 @@ func (e *Logical) Accept(visitor Visitor) (any, error) {
 }
 
 type Call struct {
-	Callee    Expr
-	Paren     token.Token
-	Arguments []Expr
+	Callee         Expr
+	Paren          token.Token
+	Arguments      []Expr
+	NamedArguments []NamedArgument // f(1, b: 2), always after the positional ones
+	Optional       bool            // f?.(), evaluates to nil without calling when f is nil
+}
+
+type NamedArgument struct {
+	Name  token.Token
+	Value Expr
 }
 
 func (e *Call) Accept(visitor Visitor) (any, error) {
 	return visitor.VisitCallExpr(e)
 }
+
+// subscript expression e.g. name[0]
+type Index struct {
+	Object   Expr
+	Bracket  token.Token // closing bracket, used to report errors
+	Index    Expr
+	Optional bool // name?.[0], evaluates to nil without indexing when name is nil
+}
+
+func (e *Index) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitIndexExpr(e)
+}
+
+// assignment to a list element e.g. xs[i] = v, or an update of one with a
+// compound assignment, ++ or --. the object and the index are evaluated
+// once, so xs[next()] += 1 only calls next once
+type IndexSet struct {
+	Object   Expr
+	Bracket  token.Token // closing bracket, used to report errors
+	Index    Expr
+	Operator token.Token // =, a compound assignment operator, ++ or --
+	Value    Expr        // nil for ++ and --
+	Prefix   bool        // ++xs[i] evaluates to the updated element
+}
+
+func (e *IndexSet) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitIndexSetExpr(e)
+}
+
+// string with embedded expressions e.g. "Hello ${name}!", the parts
+// alternate between string literals and the interpolated expressions
+type Interpolation struct {
+	Parts []Expr
+}
+
+func (e *Interpolation) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitInterpolationExpr(e)
+}
+
+// assignment combined with a binary operator e.g. a += 1, the target
+// is read and written once
+type CompoundAssign struct {
+	Name     token.Token
+	Operator token.Token // +=, -=, *=, /= or %=
+	Value    Expr
+}
+
+func (e *CompoundAssign) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitCompoundAssignExpr(e)
+}
+
+// ++ or -- on a variable, a prefix increment evaluates to the updated
+// value and a postfix one to the value before the update
+type Increment struct {
+	Name     token.Token
+	Operator token.Token // ++ or --
+	Prefix   bool
+}
+
+func (e *Increment) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitIncrementExpr(e)
+}
+
+// ternary conditional e.g. cond ? a : b
+type Conditional struct {
+	Condition  Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+func (e *Conditional) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitConditionalExpr(e)
+}
+
+// list literal e.g. [1, 2, ...rest]
+type List struct {
+	Bracket  token.Token // opening bracket
+	Elements []Expr
+}
+
+func (e *List) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitListExpr(e)
+}
+
+// ...iterable inside a list literal or the arguments of a call, inserts
+// all the values of the iterable
+type Spread struct {
+	Ellipsis   token.Token
+	Expression Expr
+}
+
+func (e *Spread) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitSpreadExpr(e)
+}
+
+// [a, [b, c], ...rest] = value, the target list only contains variables,
+// nested target lists and a final spread of a variable
+type DestructuringAssign struct {
+	Target *List
+	Value  Expr
+}
+
+func (e *DestructuringAssign) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitDestructuringAssignExpr(e)
+}
+
+// property access e.g. Color.Red, only enums and their values have properties
+type Get struct {
+	Object   Expr
+	Name     token.Token
+	Optional bool // a?.b, evaluates to nil without accessing b when a is nil
+}
+
+func (e *Get) Accept(visitor Visitor) (any, error) {
+	return visitor.VisitGetExpr(e)
+}
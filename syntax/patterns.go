@@ -0,0 +1,44 @@
+package syntax
+
+import "github.com/hamdan-khan/interpreter/token"
+
+// patterns appear in the arms of a match statement, they test a value
+// and can bind it to a name
+type Pattern interface {
+	pattern()
+}
+
+// matches values equal to the literal, Value is a literal expression,
+// a negated number literal or a property access like Color.Red
+type LiteralPattern struct {
+	Value Expr
+}
+
+// "_" matches anything
+type WildcardPattern struct {
+	Underscore token.Token
+}
+
+// matches anything and binds it to the name in the arm's scope
+type BindingPattern struct {
+	Name token.Token
+}
+
+// p1 | p2 | ... matches if any of the alternatives does
+type AlternativePattern struct {
+	Alternatives []Pattern
+}
+
+// [p1, p2, ...rest] matches a sequence of exactly as many values, or at
+// least as many with a rest name that binds the remaining ones as a list
+type ListPattern struct {
+	Bracket  token.Token // opening bracket
+	Elements []Pattern
+	Rest     *token.Token // nil without a rest name
+}
+
+func (*LiteralPattern) pattern()     {}
+func (*ListPattern) pattern()        {}
+func (*WildcardPattern) pattern()    {}
+func (*BindingPattern) pattern()     {}
+func (*AlternativePattern) pattern() {}
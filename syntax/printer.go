@@ -39,7 +39,7 @@ func (p *AstPrinter) VisitUnaryExpr(expr *Unary) (any, error) {
 }
 
 func (p *AstPrinter) VisitAssignExpr(expr *Assign) (any, error) {
-	return p.parenthesize("=", expr, expr.Value), nil
+	return p.parenthesize("=", &Variable{Name: expr.Name}, expr.Value), nil
 }
 
 func (p *AstPrinter) VisitVariableExpr(expr *Variable) (any, error) {
@@ -51,12 +51,78 @@ func (p *AstPrinter) VisitLogicalExpr(expr *Logical) (any, error) {
 }
 
 func (p *AstPrinter) VisitCallExpr(expr *Call) (any, error) {
-	args := make([]Expr, 0, len(expr.Arguments)+1)
+	args := make([]Expr, 0, len(expr.Arguments)+len(expr.NamedArguments)+1)
 	args = append(args, expr.Callee)
 	args = append(args, expr.Arguments...)
+	for _, named := range expr.NamedArguments {
+		args = append(args, named.Value)
+	}
+	if expr.Optional {
+		return p.parenthesize("?.call", args...), nil
+	}
 	return p.parenthesize("call", args...), nil
 }
 
+func (p *AstPrinter) VisitIndexExpr(expr *Index) (any, error) {
+	if expr.Optional {
+		return p.parenthesize("?.index", expr.Object, expr.Index), nil
+	}
+	return p.parenthesize("index", expr.Object, expr.Index), nil
+}
+
+func (p *AstPrinter) VisitIndexSetExpr(expr *IndexSet) (any, error) {
+	target := &Index{Object: expr.Object, Bracket: expr.Bracket, Index: expr.Index}
+	if expr.Value != nil {
+		return p.parenthesize(expr.Operator.Lexeme, target, expr.Value), nil
+	}
+	if expr.Prefix {
+		return p.parenthesize(expr.Operator.Lexeme, target), nil
+	}
+	printed, err := target.Accept(p)
+	if err != nil {
+		return nil, err
+	}
+	return "(" + printed.(string) + " " + expr.Operator.Lexeme + ")", nil
+}
+
+func (p *AstPrinter) VisitInterpolationExpr(expr *Interpolation) (any, error) {
+	return p.parenthesize("interpolate", expr.Parts...), nil
+}
+
+func (p *AstPrinter) VisitCompoundAssignExpr(expr *CompoundAssign) (any, error) {
+	return p.parenthesize(expr.Operator.Lexeme, &Variable{Name: expr.Name}, expr.Value), nil
+}
+
+func (p *AstPrinter) VisitIncrementExpr(expr *Increment) (any, error) {
+	if expr.Prefix {
+		return "(" + expr.Operator.Lexeme + " " + expr.Name.Lexeme + ")", nil
+	}
+	return "(" + expr.Name.Lexeme + " " + expr.Operator.Lexeme + ")", nil
+}
+
+func (p *AstPrinter) VisitConditionalExpr(expr *Conditional) (any, error) {
+	return p.parenthesize("?:", expr.Condition, expr.ThenBranch, expr.ElseBranch), nil
+}
+
+func (p *AstPrinter) VisitListExpr(expr *List) (any, error) {
+	return p.parenthesize("list", expr.Elements...), nil
+}
+
+func (p *AstPrinter) VisitSpreadExpr(expr *Spread) (any, error) {
+	return p.parenthesize("...", expr.Expression), nil
+}
+
+func (p *AstPrinter) VisitDestructuringAssignExpr(expr *DestructuringAssign) (any, error) {
+	return p.parenthesize("=", expr.Target, expr.Value), nil
+}
+
+func (p *AstPrinter) VisitGetExpr(expr *Get) (any, error) {
+	if expr.Optional {
+		return p.parenthesize("?."+expr.Name.Lexeme, expr.Object), nil
+	}
+	return p.parenthesize("."+expr.Name.Lexeme, expr.Object), nil
+}
+
 // parenthesize wraps expressions in Lisp-style parentheses
 // for example: parenthesize("+", left, right) produces "(+ left right)"
 func (p *AstPrinter) parenthesize(name string, exprs ...Expr) string {
@@ -11,6 +11,11 @@ type StatementVisitor interface {
 	VisitWhileStmt(expr *While) (any, error)
 	VisitFunctionStmt(expr *Function) (any, error)
 	VisitReturnStmt(expr *Return) (any, error)
+	VisitForInStmt(expr *ForIn) (any, error)
+	VisitYieldStmt(expr *Yield) (any, error)
+	VisitMatchStmt(expr *Match) (any, error)
+	VisitDestructuringVarStmt(expr *DestructuringVar) (any, error)
+	VisitEnumStmt(expr *Enum) (any, error)
 }
 
 type Stmt interface {
@@ -36,12 +41,25 @@ func (e *Print) Accept(visitor StatementVisitor) (any, error) {
 type Var struct {
 	Name        token.Token
 	Initializer Expr
+	// declared with "const" or "let", can't be assigned after initialization
+	Constant bool
 }
 
 func (e *Var) Accept(visitor StatementVisitor) (any, error) {
 	return visitor.VisitVarStmt(e)
 }
 
+// var [a, b, ...rest] = value; declares every name bound by the pattern
+type DestructuringVar struct {
+	Pattern     *ListPattern
+	Initializer Expr
+	Constant    bool
+}
+
+func (e *DestructuringVar) Accept(visitor StatementVisitor) (any, error) {
+	return visitor.VisitDestructuringVarStmt(e)
+}
+
 type Block struct {
 	Statements []Stmt
 }
@@ -71,19 +89,83 @@ func (e *While) Accept(visitor StatementVisitor) (any, error) {
 
 type Function struct {
 	Name   token.Token
-	Params []token.Token
+	Params []Parameter
 	Body   []Stmt
+	// the body contains a yield, calling the function creates a generator
+	Generator bool
 }
 
 func (e *Function) Accept(visitor StatementVisitor) (any, error) {
 	return visitor.VisitFunctionStmt(e)
 }
 
+// a function parameter e.g. a, b = 2 or ...rest
+type Parameter struct {
+	Name    token.Token
+	Default Expr // evaluated on every call that doesn't pass the argument, nil if required
+	// collects the remaining positional arguments into a list, only allowed last
+	Variadic bool
+}
+
 type Return struct {
 	Keyword token.Token
 	Value   Expr
+	// set by the resolver when Value is a call whose result is returned
+	// as is, the call can then reuse the returning function's frame
+	TailCall bool
 }
 
 func (e *Return) Accept(visitor StatementVisitor) (any, error) {
 	return visitor.VisitReturnStmt(e)
 }
+
+// for (value in iterable) body or for (key, value in iterable) body,
+// the loop variables are fresh on every iteration
+type ForIn struct {
+	Key      *token.Token // nil if only the value is bound
+	Value    token.Token
+	In       token.Token // used to report values that can't be iterated
+	Iterable Expr
+	Body     Stmt
+}
+
+func (e *ForIn) Accept(visitor StatementVisitor) (any, error) {
+	return visitor.VisitForInStmt(e)
+}
+
+// suspends a generator, handing the value to whoever asked for the next one
+type Yield struct {
+	Keyword token.Token
+	Value   Expr // nil yields nil
+}
+
+func (e *Yield) Accept(visitor StatementVisitor) (any, error) {
+	return visitor.VisitYieldStmt(e)
+}
+
+// match (subject) { pattern => statement ... }, runs the first arm
+// whose pattern matches the subject
+type Match struct {
+	Keyword token.Token
+	Subject Expr
+	Arms    []MatchArm
+}
+
+type MatchArm struct {
+	Pattern Pattern
+	Body    Stmt
+}
+
+func (e *Match) Accept(visitor StatementVisitor) (any, error) {
+	return visitor.VisitMatchStmt(e)
+}
+
+// enum Color { Red, Green, Blue } declares Color, whose values are its members
+type Enum struct {
+	Name    token.Token
+	Members []token.Token
+}
+
+func (e *Enum) Accept(visitor StatementVisitor) (any, error) {
+	return visitor.VisitEnumStmt(e)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hamdan-khan/interpreter/interpreter"
+	"github.com/hamdan-khan/interpreter/parser"
+	"github.com/hamdan-khan/interpreter/syntax"
+	"github.com/hamdan-khan/interpreter/token"
+)
+
+// test files are named "*_test.lox" and every top-level function
+// whose name starts with "test_" is a test case
+const (
+	testFileSuffix = "_test.lox"
+	testFuncPrefix = "test_"
+)
+
+type testFailure struct {
+	file    string
+	name    string
+	line    int
+	message string
+}
+
+// RunTests discovers test files under the given paths (files or directories)
+// and runs every test function in a fresh interpreter. Returns false if any test failed
+func RunTests(paths []string) bool {
+	return runTests(os.Stdout, paths)
+}
+
+// RunTests with the report written to out
+func runTests(out io.Writer, paths []string) bool {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files, err := discoverTestFiles(paths)
+	if err != nil {
+		fmt.Fprintf(out, "Error discovering tests: %v\n", err)
+		return false
+	}
+
+	passed := 0
+	failures := []testFailure{}
+	for _, file := range files {
+		p, f := runTestFile(file)
+		passed += p
+		failures = append(failures, f...)
+	}
+
+	for _, f := range failures {
+		if f.line > 0 {
+			fmt.Fprintf(out, "FAIL %s %s [line %d]: %s\n", f.file, f.name, f.line, f.message)
+		} else {
+			fmt.Fprintf(out, "FAIL %s %s: %s\n", f.file, f.name, f.message)
+		}
+	}
+	fmt.Fprintf(out, "%d passed, %d failed, %d files\n", passed, len(failures), len(files))
+	return len(failures) == 0
+}
+
+func discoverTestFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// explicitly listed files are always run, directories only contribute test files
+			if !d.IsDir() && (p == path || strings.HasSuffix(p, testFileSuffix)) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// runs every test function of a single file, each one in its own interpreter
+// so that global state can't leak between tests
+func runTestFile(path string) (passed int, failures []testFailure) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return 0, []testFailure{{file: path, message: err.Error()}}
+	}
+	scanner := token.NewScanner(string(file))
+	scanner.Scan()
+	// the scanner already reported the errors, running a partial token
+	// stream would only add confusing ones
+	if scanner.HadError {
+		return 0, []testFailure{{file: path, message: "scan error"}}
+	}
+	p := parser.NewParser(scanner.Tokens)
+	statements, err := p.Parse()
+	if err != nil {
+		return 0, []testFailure{{file: path, message: "parse error: " + err.Error()}}
+	}
+
+	for _, name := range testFunctions(statements) {
+		i := interpreter.NewInterpreter()
+		if err := interpreter.NewResolver(i).ResolveStmts(statements); err != nil {
+			return passed, append(failures, testFailure{file: path, message: "resolve error: " + err.Error()})
+		}
+
+		err := i.Interpret(statements)
+		if err == nil {
+			_, err = i.CallGlobal(name)
+		}
+		i.Close()
+		if err != nil {
+			failure := testFailure{file: path, name: name, message: err.Error()}
+			var runtimeErr *interpreter.RuntimeError
+			if errors.As(err, &runtimeErr) {
+				failure.line = runtimeErr.Token.LineNumber
+				failure.message = runtimeErr.Message
+			}
+			failures = append(failures, failure)
+			continue
+		}
+		passed++
+	}
+	return passed, failures
+}
+
+// names of the top-level test functions in declaration order
+func testFunctions(statements []syntax.Stmt) []string {
+	names := []string{}
+	for _, stmt := range statements {
+		if fn, ok := stmt.(*syntax.Function); ok && strings.HasPrefix(fn.Name.Lexeme, testFuncPrefix) {
+			names = append(names, fn.Name.Lexeme)
+		}
+	}
+	return names
+}
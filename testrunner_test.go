@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hamdan-khan/interpreter/errorHandler"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	errorHandler.Output = io.Discard
+	defer func() { errorHandler.Output = os.Stdout }()
+
+	dir := t.TempDir()
+	// every test sees the global in its initial state
+	writeFile(t, filepath.Join(dir, "state_test.lox"), `
+var count = 0;
+fun test_first() {
+  count = count + 1;
+  assertEqual(count, 1);
+}
+fun test_second() {
+  count = count + 1;
+  assertEqual(count, 1);
+}
+`)
+	// a failing test doesn't stop the ones after it
+	writeFile(t, filepath.Join(dir, "nested", "failing_test.lox"), `
+fun test_fails() {
+  assert(false, "broken");
+}
+fun test_passes() {
+  assert(true, "fine");
+}
+fun helper() {
+  assert(false, "not a test");
+}
+`)
+	writeFile(t, filepath.Join(dir, "nested", "scan_error_test.lox"), `
+fun test_never_runs() {}
+@
+`)
+	// only "*_test.lox" files are discovered in directories
+	writeFile(t, filepath.Join(dir, "notes.lox"), `
+fun test_ignored() {
+  assert(false, "ignored");
+}
+`)
+
+	var out bytes.Buffer
+	if runTests(&out, []string{dir}) {
+		t.Error("expected the run to fail")
+	}
+	report := out.String()
+
+	expected := []string{
+		"FAIL " + filepath.Join(dir, "nested", "failing_test.lox") + " test_fails [line 3]: Assertion failed: broken",
+		"FAIL " + filepath.Join(dir, "nested", "scan_error_test.lox") + " : scan error",
+		"3 passed, 2 failed, 3 files",
+	}
+	for _, line := range expected {
+		if !strings.Contains(report, line+"\n") {
+			t.Errorf("expected %q in the report:\n%s", line, report)
+		}
+	}
+	if strings.Contains(report, "ignored") || strings.Contains(report, "not a test") {
+		t.Errorf("only test functions of test files should run:\n%s", report)
+	}
+}
+
+func TestRunTestsExplicitFile(t *testing.T) {
+	dir := t.TempDir()
+	// listed files run whatever their name
+	path := filepath.Join(dir, "checks.lox")
+	writeFile(t, path, `fun test_ok() { assertEqual(1 + 1, 2); }`)
+
+	var out bytes.Buffer
+	if !runTests(&out, []string{path}) {
+		t.Errorf("expected the run to pass:\n%s", out.String())
+	}
+	if out.String() != "1 passed, 0 failed, 1 files\n" {
+		t.Errorf("unexpected summary %q", out.String())
+	}
+}
@@ -2,17 +2,42 @@ package token
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/hamdan-khan/interpreter/errorHandler"
 )
 
 type Scanner struct {
 	Tokens     []Token
+	HadError   bool   // set when any lexical error is reported
 	lineNumber int    // tracks the line number being scanned
+	lineStart  int    // byte offset where the current line starts
 	start      int    // tracks the start of the lexeme
-	current    int    // tracks the current char
+	column     int    // column of the start of the lexeme, in code points
+	current    int    // tracks the current char (byte offset, chars are utf-8 encoded)
 	source     string // contents of source file
+	// every unfinished string interpolation, innermost last
+	interpolations []interpolation
+	// triple-quoted strings split by interpolations, their segments can
+	// only be dedented once the closing quotes are found
+	multilines []*multilineString
+}
+
+type interpolation struct {
+	// brace depth inside the interpolated expression, it ends at a '}'
+	// while the depth is 0
+	braces int
+	// the string continues as a triple-quoted one after the '}'
+	multiline bool
+}
+
+type multilineString struct {
+	tokens   []int    // positions of the segment tokens in Tokens
+	segments []string // raw text of each segment
 }
 
 func NewScanner(source string) Scanner {
@@ -32,13 +57,19 @@ func (s *Scanner) Scan() {
 		// after every token addition, the new bound is where the last token ended
 		// Current state is updated in scanToken method
 		s.start = s.current
+		s.column = s.currentColumn()
 		s.scanToken()
 	}
 
+	if len(s.interpolations) > 0 {
+		s.error("", "Unterminated string interpolation.")
+	}
+
 	// to denote that we've reached the end of file
 	eofToken := Token{TokenType: EOF,
 		Lexeme:     "",
 		LineNumber: s.lineNumber,
+		Column:     s.currentColumn(),
 		Literal:    nil}
 	s.Tokens = append(s.Tokens, eofToken)
 }
@@ -52,26 +83,99 @@ func (s *Scanner) scanToken() {
 	case ')':
 		s.addToken(RIGHT_PAREN, nil)
 	case '{':
+		if n := len(s.interpolations); n > 0 {
+			s.interpolations[n-1].braces++
+		}
 		s.addToken(LEFT_BRACE, nil)
 	case '}':
-		s.addToken(RIGHT_BRACE, nil)
+		if n := len(s.interpolations); n > 0 && s.interpolations[n-1].braces == 0 {
+			// closes the interpolated expression, the rest of the string follows
+			multiline := s.interpolations[n-1].multiline
+			s.interpolations = s.interpolations[:n-1]
+			if multiline {
+				s.handleMultilineString(true)
+			} else {
+				s.handleString()
+			}
+		} else {
+			if n > 0 {
+				s.interpolations[n-1].braces--
+			}
+			s.addToken(RIGHT_BRACE, nil)
+		}
+	case '[':
+		s.addToken(LEFT_BRACKET, nil)
+	case ']':
+		s.addToken(RIGHT_BRACKET, nil)
 	case ',':
 		s.addToken(COMMA, nil)
 	case '.':
-		s.addToken(DOT, nil)
+		if s.match('.') {
+			if s.match('.') {
+				s.addToken(ELLIPSIS, nil)
+			} else {
+				s.addToken(DOT_DOT, nil)
+			}
+		} else {
+			s.addToken(DOT, nil)
+		}
 	case '-':
-		s.addToken(MINUS, nil)
+		if s.match('-') {
+			s.addToken(MINUS_MINUS, nil)
+		} else if s.match('=') {
+			s.addToken(MINUS_EQUAL, nil)
+		} else {
+			s.addToken(MINUS, nil)
+		}
 	case '+':
-		s.addToken(PLUS, nil)
+		if s.match('+') {
+			s.addToken(PLUS_PLUS, nil)
+		} else if s.match('=') {
+			s.addToken(PLUS_EQUAL, nil)
+		} else {
+			s.addToken(PLUS, nil)
+		}
 	case ';':
 		s.addToken(SEMICOLON, nil)
+	case ':':
+		s.addToken(COLON, nil)
+	case '?':
+		if s.match('?') {
+			s.addToken(QUESTION_QUESTION, nil)
+		} else if s.match('.') {
+			s.addToken(QUESTION_DOT, nil)
+		} else {
+			s.addToken(QUESTION, nil)
+		}
 	case '*':
-		s.addToken(STAR, nil)
+		if s.match('*') {
+			s.addToken(STAR_STAR, nil)
+		} else if s.match('=') {
+			s.addToken(STAR_EQUAL, nil)
+		} else {
+			s.addToken(STAR, nil)
+		}
+	case '%':
+		if s.match('=') {
+			s.addToken(PERCENT_EQUAL, nil)
+		} else {
+			s.addToken(PERCENT, nil)
+		}
+	case '&':
+		s.addToken(AMPERSAND, nil)
+	case '|':
+		s.addToken(PIPE, nil)
+	case '^':
+		s.addToken(CARET, nil)
+	case '~':
+		s.addToken(TILDE, nil)
 
 	// double char tokens
 	case '=':
 		if s.match('=') {
 			s.addToken(EQUAL_EQUAL, nil)
+		} else if s.match('>') {
+			s.addToken(FAT_ARROW, nil)
 		} else {
 			s.addToken(EQUAL, nil)
 		}
@@ -84,12 +188,16 @@ func (s *Scanner) scanToken() {
 	case '>':
 		if s.match('=') {
 			s.addToken(GREATER_EQUAL, nil)
+		} else if s.match('>') {
+			s.addToken(GREATER_GREATER, nil)
 		} else {
 			s.addToken(GREATER, nil)
 		}
 	case '<':
 		if s.match('=') {
 			s.addToken(LESS_EQUAL, nil)
+		} else if s.match('<') {
+			s.addToken(LESS_LESS, nil)
 		} else {
 			s.addToken(LESS, nil)
 		}
@@ -106,19 +214,19 @@ func (s *Scanner) scanToken() {
 		} else if s.match('*') {
 			// handles block comments /* */, advance till */ is encountered
 			for !s.isAtEnd() && !(s.next() == '*' && s.nextNext() == '/') {
-				if s.next() == '\n' {
-					s.lineNumber++
-				}
 				s.advance()
 			}
 
 			if s.isAtEnd() {
-				errorHandler.ReportError(s.lineNumber, "", "Unterminated block comment.")
+				s.error("", "Unterminated block comment.")
+				return
 			}
 
 			// consume the closing * and /
 			s.advance()
 			s.advance()
+		} else if s.match('=') {
+			s.addToken(SLASH_EQUAL, nil)
 		} else {
 			s.addToken(SLASH, nil)
 		}
@@ -126,11 +234,21 @@ func (s *Scanner) scanToken() {
 	// ignore space/tabs
 	case ' ':
 	case '\t':
-	case '\n':
-		s.lineNumber++
+	case '\r':
+	case '\n': // line tracking is done by advance
 
 	case '"':
-		s.handleString()
+		if s.match('"') {
+			if s.match('"') {
+				s.handleMultilineString(false)
+			} else {
+				s.addToken(STRING, "") // empty string
+			}
+		} else {
+			s.handleString()
+		}
+	case '`':
+		s.handleRawString()
 
 	default:
 		if s.isDigit(char) {
@@ -138,20 +256,21 @@ func (s *Scanner) scanToken() {
 		} else if s.isAlpha(char) {
 			// an alphanum identifier shouldn't start with a digit
 			s.handleIdentifier()
+		} else if char == utf8.RuneError && s.current-s.start == 1 {
+			// invalid utf-8 byte, already reported by advance
 		} else {
-			errorHandler.ReportError(s.lineNumber, fmt.Sprintf("%v", string(char)), "Unexpected character.")
+			s.error(fmt.Sprintf("at '%v'", string(char)), "Unexpected character.")
 		}
 	}
 }
 
+// identifiers can contain letters from any script, not just ascii
 func (s *Scanner) isAlpha(c rune) bool {
-	return c >= 'a' && c <= 'z' ||
-		c >= 'A' && c <= 'Z' ||
-		c == '_'
+	return unicode.IsLetter(c) || c == '_'
 }
 
 func (s *Scanner) isAlphaNum(c rune) bool {
-	return s.isDigit(c) || s.isAlpha(c)
+	return s.isDigit(c) || s.isAlpha(c) || unicode.IsDigit(c)
 }
 
 func (s *Scanner) handleIdentifier() {
@@ -168,59 +287,478 @@ func (s *Scanner) handleIdentifier() {
 	s.addToken(tokType, nil)
 }
 
+// scans a string up to its closing quote or up to the next "${", in which
+// case the expression after it is scanned as normal tokens until the
+// matching '}' and the rest of the string is scanned by another call
+//
+// "a ${b} c" is tokenized as: INTERPOLATION("a ") IDENTIFIER(b) STRING(" c")
 func (s *Scanner) handleString() {
 	for s.next() != '"' && !s.isAtEnd() {
-		if s.next() == '\n' {
-			s.lineNumber++
+		if s.next() == '$' && s.nextNext() == '{' {
+			break
+		}
+		// skip the escaped char so that \" doesn't end the string
+		if s.advance() == '\\' && !s.isAtEnd() {
+			s.advance()
 		}
-		s.advance()
 	}
 
 	if s.isAtEnd() {
-		errorHandler.ReportError(s.lineNumber, "during scanning string", "String is not terminated")
+		s.error("", "Unterminated string.")
+		return
+	}
+
+	if s.match('$') {
+		s.advance() // '{'
+		s.interpolations = append(s.interpolations, interpolation{})
+		// remove the starting '"' (or '}') and the ending "${"
+		value, ok := s.unescape(s.source[s.start+1 : s.current-2])
+		if ok {
+			s.addToken(INTERPOLATION, value)
+		}
 		return
 	}
 
 	// ending '"'
 	s.advance()
 
-	// remove the starting '"' before adding the string token
+	// remove the starting '"' (or '}') before adding the string token
+	value, ok := s.unescape(s.source[s.start+1 : s.current-1])
+	if ok {
+		s.addToken(STRING, value)
+	}
+}
+
+// raw strings are delimited by backticks, can span multiple lines
+// and don't process escape sequences e.g. `C:\path\to\file`
+func (s *Scanner) handleRawString() {
+	for s.next() != '`' && !s.isAtEnd() {
+		s.advance()
+	}
+
+	if s.isAtEnd() {
+		s.error("", "Unterminated raw string.")
+		return
+	}
+
+	// ending '`'
+	s.advance()
 	s.addToken(STRING, s.source[s.start+1:s.current-1])
 }
 
+// triple-quoted strings span multiple lines, the indentation shared by
+// all of their lines is stripped so they can be indented with the code:
+//
+//	var text = """
+//	    first line
+//	      indented line
+//	    """;
+//
+// like other strings they can be interpolated, continued is true when
+// scanning the segment after an interpolated expression's '}'
+func (s *Scanner) handleMultilineString(continued bool) {
+	segmentStart := s.start + 3
+	if continued {
+		segmentStart = s.start + 1
+	}
+	for !strings.HasPrefix(s.source[s.current:], `"""`) && !s.isAtEnd() {
+		if s.next() == '$' && s.nextNext() == '{' {
+			break
+		}
+		if s.advance() == '\\' && !s.isAtEnd() {
+			s.advance()
+		}
+	}
+
+	if s.isAtEnd() {
+		s.error("", "Unterminated multi-line string.")
+		return
+	}
+
+	if s.match('$') {
+		s.advance() // '{'
+		s.interpolations = append(s.interpolations, interpolation{multiline: true})
+		if !continued {
+			s.multilines = append(s.multilines, &multilineString{})
+		}
+		// the value is only known once the whole string is scanned
+		s.addToken(INTERPOLATION, nil)
+		s.addSegment(s.source[segmentStart : s.current-2])
+		return
+	}
+
+	// ending '"""'
+	s.advance()
+	s.advance()
+	s.advance()
+
+	if !continued {
+		value, ok := s.unescape(dedent(s.source[segmentStart : s.current-3]))
+		if ok {
+			s.addToken(STRING, value)
+		}
+		return
+	}
+
+	s.addToken(STRING, nil)
+	s.addSegment(s.source[segmentStart : s.current-3])
+	str := s.multilines[len(s.multilines)-1]
+	s.multilines = s.multilines[:len(s.multilines)-1]
+	for idx, segment := range dedentSegments(str.segments) {
+		if value, ok := s.unescape(segment); ok {
+			s.Tokens[str.tokens[idx]].Literal = value
+		}
+	}
+}
+
+// records the last added token as a segment of the innermost triple-quoted string
+func (s *Scanner) addSegment(text string) {
+	str := s.multilines[len(s.multilines)-1]
+	str.tokens = append(str.tokens, len(s.Tokens)-1)
+	str.segments = append(str.segments, text)
+}
+
+// removes the leading newline, the whitespace-only closing line and the
+// common indentation of the non-blank lines of a multi-line string
+func dedent(text string) string {
+	return dedentSegments([]string{text})[0]
+}
+
+// dedent for a string split into segments by interpolated expressions.
+// a line holding an interpolation isn't blank, whatever its text
+func dedentSegments(segments []string) []string {
+	for idx := range segments {
+		segments[idx] = strings.ReplaceAll(segments[idx], "\r\n", "\n")
+	}
+	segments[0] = strings.TrimPrefix(segments[0], "\n")
+	last := len(segments) - 1
+	if end := strings.LastIndexByte(segments[last], '\n'); end >= 0 && strings.TrimSpace(segments[last][end:]) == "" {
+		segments[last] = segments[last][:end]
+	}
+
+	// a line starts at the beginning of the string and after every newline,
+	// its text within the segment runs up to the next newline
+	type line struct {
+		segment, start, end int
+		blank               bool
+	}
+	lines := []line{}
+	for idx, segment := range segments {
+		offset := 0
+		if idx > 0 {
+			// up to its first newline, a segment continues the line of the interpolation
+			newline := strings.IndexByte(segment, '\n')
+			if newline < 0 {
+				continue
+			}
+			offset = newline + 1
+		}
+		for {
+			end := strings.IndexByte(segment[offset:], '\n')
+			// a line cut short by an interpolation isn't blank
+			ended := end >= 0 || idx == last
+			if end < 0 {
+				end = len(segment)
+			} else {
+				end += offset
+			}
+			blank := ended && strings.TrimSpace(segment[offset:end]) == ""
+			lines = append(lines, line{segment: idx, start: offset, end: end, blank: blank})
+			if end == len(segment) {
+				break
+			}
+			offset = end + 1
+		}
+	}
+
+	indent := ""
+	found := false
+	for _, l := range lines {
+		if l.blank {
+			continue
+		}
+		text := segments[l.segment][l.start:l.end]
+		lineIndent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+		if !found {
+			indent = lineIndent
+			found = true
+			continue
+		}
+		// shrink the indent to the prefix both lines share
+		for !strings.HasPrefix(lineIndent, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+
+	// rebuilt back to front, so the offsets of earlier lines stay valid
+	for idx := len(lines) - 1; idx >= 0; idx-- {
+		l := lines[idx]
+		segment := segments[l.segment]
+		text := segment[l.start:l.end]
+		if l.blank {
+			text = ""
+		} else {
+			text = strings.TrimPrefix(text, indent)
+		}
+		segments[l.segment] = segment[:l.start] + text + segment[l.end:]
+	}
+	return segments
+}
+
+// replaces escape sequences with the chars they represent, supported ones are
+// \n \t \r \0 \" \$ \\ and \u{...} with 1 to 6 hex digits e.g. \u{1F600}
+//
+// reports invalid escape sequences and returns false if any is found
+func (s *Scanner) unescape(text string) (string, bool) {
+	if !strings.ContainsRune(text, '\\') {
+		return text, true
+	}
+
+	var builder strings.Builder
+	ok := true
+	for idx := 0; idx < len(text); idx++ {
+		if text[idx] != '\\' {
+			builder.WriteByte(text[idx])
+			continue
+		}
+		if idx+1 >= len(text) {
+			s.error("at '\\'", "Invalid escape sequence.")
+			return "", false
+		}
+
+		idx++
+		switch text[idx] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		case '0':
+			builder.WriteByte(0)
+		case '"':
+			builder.WriteByte('"')
+		case '$':
+			builder.WriteByte('$')
+		case '\\':
+			builder.WriteByte('\\')
+		case 'u':
+			// \u{XXXX}
+			end := strings.IndexByte(text[idx:], '}')
+			if !strings.HasPrefix(text[idx:], "u{") || end < 0 {
+				s.error("at '\\u'", "Invalid unicode escape, expected \\u{...}.")
+				ok = false
+				continue
+			}
+			digits := text[idx+2 : idx+end]
+			code, err := strconv.ParseUint(digits, 16, 32)
+			if err != nil || len(digits) > 6 || !utf8.ValidRune(rune(code)) {
+				s.error("at '\\u{"+digits+"}'", "Invalid unicode code point.")
+				ok = false
+			} else {
+				builder.WriteRune(rune(code))
+			}
+			idx += end
+		default:
+			r, _ := utf8.DecodeRuneInString(text[idx:])
+			s.error("at '\\"+string(r)+"'", "Invalid escape sequence.")
+			ok = false
+		}
+	}
+	return builder.String(), ok
+}
+
 func (s *Scanner) isDigit(c rune) bool {
 	return '0' <= c && c <= '9'
 }
 
-// scans and tokenizes numbers
+// number literal bases, keyed by the char following the leading 0
+var radixPrefixes = map[rune]struct {
+	base int
+	name string
+}{
+	'x': {16, "hexadecimal"}, 'X': {16, "hexadecimal"},
+	'o': {8, "octal"}, 'O': {8, "octal"},
+	'b': {2, "binary"}, 'B': {2, "binary"},
+}
+
+// scans and tokenizes numbers, supported forms are:
+// 123, 123.45, 1_000_000, 1e-9, 1.5E3, 0xFF, 0o17 and 0b1010
+//
+// numbers with a fractional part or an exponent are floats (float64),
+// all other ones are integers (int64). The "n" suffix makes a big integer
+// (*big.Int) e.g. 123n, the "d" suffix an exact decimal (*big.Rat) e.g. 0.1d
 func (s *Scanner) handleNumber() {
-	for s.isDigit(s.next()) {
-		s.advance()
+	if s.source[s.start] == '0' {
+		if radix, ok := radixPrefixes[s.next()]; ok {
+			s.advance()
+			s.handleRadixNumber(radix.base, radix.name)
+			return
+		}
 	}
 
+	valid := s.digits()
+	isFloat := false
+
 	// if the char after the decimal point is a digit,
 	// its the fractional part. In some cases, it can be a method too
 	if s.next() == '.' && s.isDigit(s.nextNext()) {
 		// pointer moved to the decimal point
 		s.advance()
+		isFloat = true
 
 		// advance through the fractional part
-		for s.isDigit(s.next()) {
+		valid = s.digits() && valid
+	}
+
+	if s.next() == 'e' || s.next() == 'E' {
+		s.advance()
+		isFloat = true
+		if s.next() == '+' || s.next() == '-' {
 			s.advance()
 		}
+		if !s.isDigit(s.next()) {
+			s.error("at '"+s.source[s.start:s.current]+"'", "Empty exponent in number literal.")
+			return
+		}
+		valid = s.digits() && valid
+	}
+
+	digits := strings.ReplaceAll(s.source[s.start:s.current], "_", "")
+	suffix := s.next()
+	if (suffix == 'n' && !isFloat) || suffix == 'd' {
+		s.advance()
+	}
+
+	lexeme := s.source[s.start:s.current]
+	if !valid {
+		s.error("at '"+lexeme+"'", "Digit separator '_' must be between digits.")
+		return
 	}
 
-	numLiteral, err := strconv.ParseFloat(s.source[s.start:s.current], 64)
+	var numLiteral any
+	var err error
+	if suffix == 'n' && !isFloat {
+		numLiteral, err = parseBigInt(digits, 10)
+	} else if suffix == 'd' {
+		numLiteral, err = parseDecimal(digits)
+	} else if isFloat {
+		numLiteral, err = strconv.ParseFloat(digits, 64)
+	} else {
+		numLiteral, err = strconv.ParseInt(digits, 10, 64)
+	}
 	if err != nil {
-		errorHandler.ReportError(s.lineNumber, "", "Unexpected number encountered")
+		s.error("at '"+lexeme+"'", "Number literal can't be represented.")
+		return
 	}
 	s.addToken(NUMBER, numLiteral)
 }
 
-// returns the char at the current pointer and increments the curr pointer
+// consumes a run of digits that may be separated by single underscores,
+// returns false if an underscore isn't followed by a digit
+func (s *Scanner) digits() bool {
+	valid := true
+	for s.isDigit(s.next()) || s.next() == '_' {
+		if s.advance() == '_' && !s.isDigit(s.next()) {
+			valid = false
+		}
+	}
+	return valid
+}
+
+// scans the digits of a number with a 0x, 0o or 0b prefix
+func (s *Scanner) handleRadixNumber(base int, name string) {
+	// consume every alphanumeric char so that malformed
+	// literals like 0b102 or 0xZZ are reported as a whole
+	for s.isAlphaNum(s.next()) {
+		s.advance()
+	}
+	lexeme := s.source[s.start:s.current]
+	body := lexeme[2:]
+	isBig := strings.HasSuffix(body, "n")
+	body = strings.TrimSuffix(body, "n")
+
+	if body == "" {
+		s.error("at '"+lexeme+"'", "Missing digits after '"+lexeme+"'.")
+		return
+	}
+	if body[0] == '_' || body[len(body)-1] == '_' || strings.Contains(body, "__") {
+		s.error("at '"+lexeme+"'", "Digit separator '_' must be between digits.")
+		return
+	}
+	for _, c := range body {
+		if c != '_' && !isRadixDigit(c, base) {
+			s.error("at '"+lexeme+"'", fmt.Sprintf("Invalid digit '%c' in %s literal.", c, name))
+			return
+		}
+	}
+
+	var value any
+	var err error
+	if isBig {
+		value, err = parseBigInt(strings.ReplaceAll(body, "_", ""), base)
+	} else {
+		value, err = strconv.ParseInt(strings.ReplaceAll(body, "_", ""), base, 64)
+	}
+	if err != nil {
+		s.error("at '"+lexeme+"'", "Number literal can't be represented.")
+		return
+	}
+	s.addToken(NUMBER, value)
+}
+
+func parseBigInt(digits string, base int) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid big integer %q", digits)
+	}
+	return value, nil
+}
+
+// largest exponent allowed in a decimal literal, bigger ones would
+// allocate an absurd amount of digits e.g. 1e999999999d
+const maxDecimalExponent = 10000
+
+func parseDecimal(digits string) (*big.Rat, error) {
+	if idx := strings.IndexAny(digits, "eE"); idx >= 0 {
+		exponent, err := strconv.Atoi(digits[idx+1:])
+		if err != nil || exponent > maxDecimalExponent || exponent < -maxDecimalExponent {
+			return nil, fmt.Errorf("decimal exponent out of range %q", digits)
+		}
+	}
+	value, ok := new(big.Rat).SetString(digits)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", digits)
+	}
+	return value, nil
+}
+
+func isRadixDigit(c rune, base int) bool {
+	digit := -1
+	switch {
+	case '0' <= c && c <= '9':
+		digit = int(c - '0')
+	case 'a' <= c && c <= 'f':
+		digit = int(c-'a') + 10
+	case 'A' <= c && c <= 'F':
+		digit = int(c-'A') + 10
+	}
+	return digit >= 0 && digit < base
+}
+
+// returns the char at the current pointer and moves the curr pointer past it
+//
+// chars are decoded as utf-8, so a single char can span multiple bytes
 func (s *Scanner) advance() rune {
-	curr := rune(s.source[s.current])
-	s.current++
+	curr, size := utf8.DecodeRuneInString(s.source[s.current:])
+	if curr == utf8.RuneError && size == 1 {
+		s.error(fmt.Sprintf("at column %d", s.currentColumn()), "Invalid UTF-8 encoding.")
+	}
+	s.current += size
+	if curr == '\n' {
+		s.lineNumber++
+		s.lineStart = s.current
+	}
 	return curr
 }
 
@@ -232,11 +770,11 @@ func (s *Scanner) match(char rune) bool {
 	}
 	// why check with current?
 	// because current is already incremented using advance in scanToken
-	if rune(s.source[s.current]) != char {
+	if s.next() != char {
 		return false
 	}
 
-	s.current++
+	s.advance()
 	return true
 }
 
@@ -245,15 +783,28 @@ func (s *Scanner) next() rune {
 	if s.isAtEnd() {
 		return '\000'
 	} // null terminator
-	return rune(s.source[s.current])
+	curr, _ := utf8.DecodeRuneInString(s.source[s.current:])
+	return curr
 }
 
 // returns the char after the next char
 func (s *Scanner) nextNext() rune {
-	if s.current+1 >= len(s.source) {
+	_, size := utf8.DecodeRuneInString(s.source[s.current:])
+	if s.current+size >= len(s.source) {
 		return '\000'
 	} // null terminator
-	return rune(s.source[s.current+1])
+	curr, _ := utf8.DecodeRuneInString(s.source[s.current+size:])
+	return curr
+}
+
+// column of the current pointer, counted in code points from the start of the line
+func (s *Scanner) currentColumn() int {
+	return utf8.RuneCountInString(s.source[s.lineStart:s.current]) + 1
+}
+
+func (s *Scanner) error(location string, message string) {
+	s.HadError = true
+	errorHandler.ReportError(s.lineNumber, location, message)
 }
 
 func (s *Scanner) isAtEnd() bool {
@@ -265,6 +816,7 @@ func (s *Scanner) addToken(tokenType TokenType, literal any) {
 	token := Token{TokenType: tokenType,
 		Literal:    literal,
 		Lexeme:     lexeme,
-		LineNumber: s.lineNumber}
+		LineNumber: s.lineNumber,
+		Column:     s.column}
 	s.Tokens = append(s.Tokens, token)
 }
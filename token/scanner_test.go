@@ -0,0 +1,45 @@
+package token
+
+import (
+	"io"
+	"testing"
+
+	"github.com/hamdan-khan/interpreter/errorHandler"
+)
+
+func FuzzScanner(f *testing.F) {
+	seeds := []string{
+		"",
+		"var a = 1;",
+		"print \"hello\" + \"world\";",
+		"fun sum(a, b) { return a + b; }",
+		"// comment\n/* block\ncomment */",
+		"/* unterminated",
+		"/*",
+		"\"unterminated",
+		"123.456 123. .5",
+		"0xFF 0b1010 0o17 1e-9 1.5E3 1_000_000",
+		"0x 0b2 1_ 1e 1e400 0x1_0000_0000_0000_0000",
+		"123n 0xFFn 0.1d 1.5e3d 1e999999999d 1.5n",
+		"!= == <= >= < > ! =",
+		"@#$",
+		"\xff\xfe",
+		"é ü ☃",
+		"\"\"\"\n  a ${b}\n  ${\"\"\"\n c\n \"\"\"} d\n  \"\"\"",
+		"\"\"\"${",
+		"\"\"\"${}\"\"\"${",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	errorHandler.Output = io.Discard
+	f.Fuzz(func(t *testing.T, source string) {
+		scanner := NewScanner(source)
+		scanner.Scan()
+
+		if len(scanner.Tokens) == 0 || scanner.Tokens[len(scanner.Tokens)-1].TokenType != EOF {
+			t.Fatalf("token stream must end with EOF")
+		}
+	})
+}
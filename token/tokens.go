@@ -8,6 +8,8 @@ const (
 	RIGHT_PAREN
 	LEFT_BRACE
 	RIGHT_BRACE
+	LEFT_BRACKET
+	RIGHT_BRACKET
 	COMMA
 	DOT
 	MINUS
@@ -15,6 +17,13 @@ const (
 	SEMICOLON
 	SLASH
 	STAR
+	PERCENT
+	AMPERSAND
+	PIPE
+	CARET
+	TILDE
+	QUESTION
+	COLON
 
 	// possible doule char tokens
 	EXCLAMATION
@@ -25,10 +34,26 @@ const (
 	GREATER_EQUAL
 	LESS
 	LESS_EQUAL
+	STAR_STAR         // **
+	LESS_LESS         // <<
+	GREATER_GREATER   // >>
+	PLUS_EQUAL        // +=
+	MINUS_EQUAL       // -=
+	STAR_EQUAL        // *=
+	SLASH_EQUAL       // /=
+	PERCENT_EQUAL     // %=
+	PLUS_PLUS         // ++
+	MINUS_MINUS       // --
+	QUESTION_QUESTION // ??
+	DOT_DOT           // ..
+	FAT_ARROW         // =>
+	ELLIPSIS          // ...
+	QUESTION_DOT      // ?.
 
 	// literals
 	IDENTIFIER
 	STRING
+	INTERPOLATION // string segment followed by an interpolated expression e.g. "Hello ${
 	NUMBER
 
 	// keywords
@@ -38,11 +63,17 @@ const (
 	FALSE
 	FUNCTION
 	RETURN
+	YIELD
 	FOR
 	WHILE
+	IN
+	MATCH
 	IF
 	ELSE
 	VAR
+	CONST
+	LET
+	ENUM
 	NIL
 	PRINT
 
@@ -54,16 +85,22 @@ type Token struct {
 	TokenType  TokenType
 	Lexeme     string
 	LineNumber int
+	Column     int // column of the first char of the lexeme, in code points
 	Literal    any
 }
 
 var ReservedKeywords = map[string]TokenType{
 	"and":    AND,
+	"const":  CONST,
 	"else":   ELSE,
+	"enum":   ENUM,
 	"false":  FALSE,
 	"for":    FOR,
 	"fun":    FUNCTION,
 	"if":     IF,
+	"in":     IN,
+	"let":    LET,
+	"match":  MATCH,
 	"nil":    NIL,
 	"or":     OR,
 	"print":  PRINT,
@@ -71,4 +108,5 @@ var ReservedKeywords = map[string]TokenType{
 	"true":   TRUE,
 	"var":    VAR,
 	"while":  WHILE,
+	"yield":  YIELD,
 }